@@ -39,6 +39,13 @@ dvi_log:
 	@go doc -all ${INT_FLDR}/${LOG_FLDR}/${LOG_PKG}
 .PHONY: dvi_log
 
+fuzz:
+	@for target in $$(go test -list '^Fuzz' ./${INT_FLDR}/${CALC_FLDR} | grep '^Fuzz'); do \
+		echo "Fuzzing $$target for ${FUZZ_TIME}..."; \
+		go test -run='^$$' -fuzz="^$$target$$" -fuzztime=${FUZZ_TIME} ./${INT_FLDR}/${CALC_FLDR} || exit 1; \
+	done
+.PHONY: fuzz
+
 coverage:
 	go test -v -cover -coverprofile=${COVERAGE_REPORT} ./${INT_FLDR}/...
 	go tool cover -html=${COVERAGE_REPORT} -o ./${COVERAGE_REPORT}.html
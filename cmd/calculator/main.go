@@ -3,23 +3,232 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/tdutanton/go_console_projects/internal/calculator"
 )
 
+// errRowsFailed signals that batch mode wrote its output but some rows could not be evaluated.
+var errRowsFailed = errors.New("some rows could not be evaluated")
+
+// modes of the calculator by the first command line argument
+var modes = map[string]func(args []string) error{
+	"":      runClassic,
+	"eval":  runEval,
+	"batch": runBatch,
+	"rpn":   runRPN,
+}
+
 // Main function for Smart Calculator function.
-// Takes left operand, operator and right operand with ENTER after every one input
+//
+// Without a mode it takes left operand, operator and right operand with ENTER after every one input.
+// "calc eval EXPR" evaluates an expression, "calc eval" without an expression evaluates every line of input.
+// "calc batch --in data.csv --expr EXPR --out result.csv" evaluates an expression for every CSV row.
+// "calc rpn" is a Reverse Polish Notation calculator printing the stack after every line of input.
+// Numbers are read and printed in the locale given by -locale or by the
+// first of the CALC_LOCALE, LC_ALL, LC_NUMERIC and LANG environment variables naming
+// a supported locale, results are rounded to -precision digits.
+// Expressions may contain intervals like [1.9, 2.1], -interval shows the rounding error of every operation.
 func main() {
+	mode, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		mode, args = args[0], args[1:]
+	}
+	run, ok := modes[mode]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown mode %q, use eval, batch, rpn or no mode at all\n", mode)
+		os.Exit(2)
+	}
+	if err := run(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// settings holds the flags shared by every mode.
+type settings struct {
+	locale    *string
+	precision *int
+	interval  *bool
+}
+
+// newSettings defines the shared flags in fs.
+func newSettings(fs *flag.FlagSet) settings {
+	return settings{
+		locale:    fs.String("locale", "", "number format: C, en, de, de-CH, fr, ru (default $"+calculator.LocaleEnv+", $LC_ALL, $LC_NUMERIC or $LANG)"),
+		precision: fs.Int("precision", calculator.DefaultPrecision, "fractional digits of results, -1 for full precision"),
+		interval:  fs.Bool("interval", false, "interval mode: round every operation outward and show the bounds of the result"),
+	}
+}
+
+// options returns calculator options from parsed flags and the environment.
+func (s settings) options() (calculator.Options, error) {
+	opts := calculator.Options{Locale: calculator.LocaleFromEnv(), Precision: *s.precision, Interval: *s.interval}
+	if *s.locale != "" {
+		l, err := calculator.LookupLocale(*s.locale)
+		if err != nil {
+			return opts, err
+		}
+		opts.Locale = l
+	}
+	return opts, nil
+}
+
+// runClassic asks for two operands and an operator and prints the result.
+func runClassic(args []string) error {
+	fs := flag.NewFlagSet("calc", flag.ExitOnError)
+	s := newSettings(fs)
+	fs.Parse(args)
+	opts, err := s.options()
+	if err != nil {
+		return err
+	}
 	reader := bufio.NewReader(os.Stdin)
-	left, _ := calculator.ParseOperand("Input left operand: ", reader, os.Stdout)
+	left, _ := calculator.ParseLocaleOperand("Input left operand: ", opts.Locale, reader, os.Stdout)
 	operator, _ := calculator.ParseOperator("Input one of operations  + - * /: ", reader, os.Stdout)
-	right, _ := calculator.ParseOperand("Input right operand: ", reader, os.Stdout)
-	res, err := calculator.CreateOperation(left, operator, right)
+	right, _ := calculator.ParseLocaleOperand("Input right operand: ", opts.Locale, reader, os.Stdout)
+	var res calculator.Interval
+	if opts.Interval {
+		res, err = opts.OperateInterval(calculator.Point(left), operator, calculator.Point(right))
+	} else {
+		var v float64
+		v, err = opts.Operate(left, operator, right)
+		res = calculator.Point(v)
+	}
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
+	}
+	fmt.Println("Result:", opts.FormatInterval(res))
+	return nil
+}
+
+// runEval evaluates the expression given in args or, if there is none,
+// every line of input reporting errors without stopping.
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("calc eval", flag.ExitOnError)
+	s := newSettings(fs)
+	fs.Parse(args)
+	opts, err := s.options()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		res, err := calculator.EvaluateInterval(strings.Join(fs.Args(), " "), opts)
+		if err != nil {
+			return err
+		}
+		fmt.Println(opts.FormatInterval(res))
+		return nil
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			res, evalErr := calculator.EvaluateInterval(line, opts)
+			if evalErr != nil {
+				fmt.Println("Error:", evalErr)
+			} else {
+				fmt.Println(opts.FormatInterval(res))
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// runBatch evaluates an expression for every row of a CSV file using column
+// names as variables and writes the rows with the result column appended.
+// Rows which fail are reported to stderr.
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("calc batch", flag.ExitOnError)
+	s := newSettings(fs)
+	in := fs.String("in", "", "input CSV file with a header row (default stdin)")
+	out := fs.String("out", "", "output CSV file (default stdout)")
+	expr := fs.String("expr", "", "expression using column names as variables")
+	column := fs.String("column", calculator.DefaultResultColumn, "name of the result column")
+	sep := fs.String("sep", ",", "field delimiter")
+	fs.Parse(args)
+	opts, err := s.options()
+	if err != nil {
+		return err
+	}
+	if *expr == "" {
+		return errors.New("batch mode needs --expr")
+	}
+	comma := []rune(*sep)
+	if len(comma) != 1 {
+		return fmt.Errorf("invalid delimiter %q", *sep)
+	}
+	e, err := calculator.ParseExpr(*expr, opts.Locale)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = f
+	}
+	var writer io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		writer = f
+	}
+	b := calculator.Batch{Expr: e, Column: *column, Comma: comma[0], Options: opts}
+	rowErrors, err := b.Run(reader, writer)
+	for _, rowErr := range rowErrors {
+		fmt.Fprintln(os.Stderr, rowErr)
+	}
+	if err != nil {
+		return err
+	}
+	if len(rowErrors) > 0 {
+		return fmt.Errorf("%w: %d failed", errRowsFailed, len(rowErrors))
+	}
+	return nil
+}
+
+// runRPN executes every line of input on the RPN stack and prints the stack,
+// a line which fails is reported and leaves the stack unchanged.
+func runRPN(args []string) error {
+	fs := flag.NewFlagSet("calc rpn", flag.ExitOnError)
+	s := newSettings(fs)
+	fs.Parse(args)
+	opts, err := s.options()
+	if err != nil {
+		return err
+	}
+	c := calculator.RPN{Options: opts}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			if execErr := c.Exec(line); execErr != nil {
+				fmt.Println("Error:", execErr)
+			}
+			c.PrintStack(os.Stdout)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 	}
-	fmt.Println("Result:", res)
 }
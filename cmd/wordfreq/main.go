@@ -3,27 +3,765 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tdutanton/go_console_projects/internal/wordfreq"
+	"golang.org/x/term"
 )
 
+// defaultK is the number of words shown when -k is not given.
+const defaultK = 10
+
 // Main function for analyzing word frequency in text.
+//
+// "wordfreq -k 20 file1.txt file2.txt" prints the 20 most frequent words of the files,
+// without files (or with "-") the text is read from stdin until EOF.
+// Run in a terminal without files and -k it asks for a line of words and K.
+//
+// Words are case folded and split at punctuation, -fields splits at whitespace only.
+// -tie sets the order of words with equal frequency: lex (default), first, last or length.
+// Files are counted by -workers goroutines in parallel, one per CPU by default.
+// -stop en,ru and -stopfile my.txt leave stop words out.
+// -include and -exclude count only the words matching or not matching a regular
+// expression, -minlen and -maxlen only the words of the given numbers of characters;
+// -nonum leaves out numbers, -nourls URLs and -noemails email addresses,
+// which are then kept whole rather than split into words.
+// -stem en, ru or auto counts words by their stems, -forms also prints
+// the forms of the text merged into every stem.
+// -n 2 counts bigrams, -n 3 trigrams and so on, printed one per line;
+// with -chars n-grams are of characters of every word.
+// -show rank,count,freq (or all) prints every word on its own line with its rank,
+// number of occurrences and share of all words in percent.
+// -format json, ndjson, csv, tsv or markdown prints the rank, count
+// and frequency of every word for other programs.
+// -bars prints a bar chart of the words with their counts and frequencies fitting
+// the terminal, -width sets its width: that of the terminal, COLUMNS or 80 characters by default.
+// -svg cloud.svg writes a word cloud of the words to a file, -size sets its size.
+// -approx N counts in fixed memory and prints every word with its estimated
+// count and the largest possible overestimate.
+// -compare ref.txt compares the files with the reference ones and prints the K words
+// most overused and the K most underused in them with their keyness statistics,
+// sorted by -by ll, chi2 or logratio; -min and -minll leave out rare and
+// insignificant differences.
+// -tfidf treats every file, and every file in the given directories, as a document
+// and prints the K words of each with the highest TF-IDF, the words distinguishing it
+// from the other documents.
+// -index words.idx prints the K most frequent words of an index file without reading
+// the documents again; with -add the files are counted and appended to it as documents,
+// -merge a.idx,b.idx merges other index files into it. Words are counted as they are
+// added, so the flags changing how they are counted may be given only with -add,
+// and -add must not be run on the same index file by several processes at once.
+// -trend 1h reads lines starting with timestamps, e.g. chat logs, and prints the K words
+// of every hour whose frequency rose most compared with the hour before; -step 15m
+// makes the windows slide by 15 minutes. -by, -min and -minll work like with -compare.
+// -stats prints the numbers of words, distinct words, hapax legomena and sentences,
+// the type-token ratio, average word and sentence lengths and readability indices
+// of the text instead of its words.
+// -near error prints the K words most strongly associated with "error" among those
+// occurring at most -window words from it, by -score tscore, pmi or dice;
+// -min leaves out words occurring near it fewer times.
+//
+// At most one of -approx, -index, -trend, -stats, -near, -tfidf and -compare may be
+// given; conflicting flags and files a mode does not read are rejected with exit code 2.
 func main() {
+	k := flag.Int("k", defaultK, "number of most frequent words to show")
+	tie := flag.String("tie", wordfreq.Lexicographic.String(), "order of words with equal frequency: lex, first, last or length")
+	fields := flag.Bool("fields", false, "split words at whitespace only, keeping case and punctuation")
+	workers := flag.Int("workers", 0, "number of goroutines counting words, 0 for one per CPU")
+	approx := flag.Int("approx", 0, "approximate counting in fixed memory monitoring at most N words, 0 for exact counting")
+	compare := flag.String("compare", "", "comma-separated reference files to compare the text with")
+	by := flag.String("by", wordfreq.LogLikelihood.String(), "statistic to sort compared words by: ll, chi2 or logratio")
+	minCount := flag.Int("min", 5, "least number of occurrences of a compared word in both texts, or of a word near the -near one")
+	minLL := flag.Float64("minll", 0, "least log-likelihood of a compared word, 3.84 for p < 0.05")
+	tfidf := flag.Bool("tfidf", false, "print the words of every file or file in a directory with the highest TF-IDF")
+	index := flag.String("index", "", "index file to print the most frequent words of, to add files to or to merge indexes into")
+	add := flag.Bool("add", false, "count the files and append them to the -index file as documents")
+	merge := flag.String("merge", "", "comma-separated index files to merge into the -index file")
+	trend := flag.Duration("trend", 0, "size of windows of time to find trending words of timestamped lines in, e.g. 1h")
+	step := flag.Duration("step", 0, "distance between starts of sliding -trend windows, the window size by default")
+	stats := flag.Bool("stats", false, "print statistics and readability indices of the text instead of its words")
+	near := flag.String("near", "", "word to print the words occurring near of, with their association scores")
+	window := flag.Int("window", 5, "number of words on either side of the -near word counted as near")
+	score := flag.String("score", wordfreq.TScore.String(), "association measure to sort words near the -near one by: tscore, pmi or dice")
+	stop := flag.String("stop", "", "comma-separated built-in stop word lists to leave out: "+strings.Join(wordfreq.StopWordLanguages(), ", "))
+	stopFile := flag.String("stopfile", "", "comma-separated files with stop words separated by whitespace")
+	include := flag.String("include", "", "regular expression words must match to be counted")
+	exclude := flag.String("exclude", "", "regular expression of words to leave out")
+	minLen := flag.Int("minlen", 0, "least number of characters of a counted word")
+	maxLen := flag.Int("maxlen", 0, "largest number of characters of a counted word, 0 for no limit")
+	noNum := flag.Bool("nonum", false, "leave out numbers")
+	noURLs := flag.Bool("nourls", false, "leave out URLs")
+	noEmails := flag.Bool("noemails", false, "leave out email addresses")
+	stem := flag.String("stem", "", "count words by their stems: en, ru or auto for both")
+	forms := flag.Bool("forms", false, "print the forms merged into every stem, with -stem")
+	n := flag.Int("n", 1, "count n-grams of n words instead of single words")
+	chars := flag.Bool("chars", false, "count n-grams of characters of every word, with -n")
+	show := flag.String("show", "", "comma-separated columns to print next to words: rank, count, freq or all")
+	format := flag.String("format", wordfreq.Text.String(), "output format: text, json, ndjson, csv, tsv or markdown")
+	bars := flag.Bool("bars", false, "print a bar chart of the words with their counts and frequencies")
+	width := flag.Int("width", terminalWidth(), "width of the -bars chart in characters")
+	svg := flag.String("svg", "", "SVG file to write a word cloud of the words to")
+	size := flag.String("size", "800x600", "width and height of the -svg word cloud in pixels")
+	flag.Parse()
+	tieBreak, err := wordfreq.ParseTieBreak(*tie)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *k <= 0 {
+		fmt.Fprintln(os.Stderr, "-k must be positive")
+		os.Exit(2)
+	}
+	if *n <= 0 {
+		fmt.Fprintln(os.Stderr, "-n must be positive")
+		os.Exit(2)
+	}
+	cols, err := wordfreq.ParseColumns(*show)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	outFormat, err := wordfreq.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	statistic, err := wordfreq.ParseStatistic(*by)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	association, err := wordfreq.ParseAssociation(*score)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	modes := setFlags(map[string]bool{
+		"-approx": *approx > 0, "-index": *index != "", "-trend": *trend != 0, "-stats": *stats,
+		"-near": *near != "", "-tfidf": *tfidf, "-compare": *compare != "",
+	})
+	if len(modes) > 1 {
+		fmt.Fprintln(os.Stderr, strings.Join(modes, ", ")+" cannot be used together")
+		os.Exit(2)
+	}
+	if (*add || *merge != "") && *index == "" {
+		fmt.Fprintln(os.Stderr, "-add and -merge need -index")
+		os.Exit(2)
+	}
+	if *add && *merge != "" {
+		fmt.Fprintln(os.Stderr, "-add and -merge cannot be used together")
+		os.Exit(2)
+	}
+	if *index != "" && !*add && flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "-index takes files only with -add, got "+strings.Join(flag.Args(), " "))
+		os.Exit(2)
+	}
+	if *index != "" && !*add {
+		// an index keeps the counts of the words as they were added
+		var counting []string
+		for _, name := range []string{"fields", "stop", "stopfile", "include", "exclude", "minlen", "maxlen",
+			"nonum", "nourls", "noemails", "stem", "n", "chars"} {
+			if isFlagSet(name) {
+				counting = append(counting, "-"+name)
+			}
+		}
+		if len(counting) > 0 {
+			fmt.Fprintln(os.Stderr, strings.Join(counting, ", ")+" can be used with -index only with -add")
+			os.Exit(2)
+		}
+	}
+	if len(modes) > 0 && modes[0] != "-index" && (cols != 0 || *bars || *svg != "") {
+		fmt.Fprintln(os.Stderr, "-show, -bars and -svg cannot be used with "+modes[0])
+		os.Exit(2)
+	}
+	if outFormat != wordfreq.Text && (*forms || *approx > 0 || *compare != "" || *tfidf || *trend != 0 || *near != "" || *stats) {
+		fmt.Fprintln(os.Stderr, "-forms, -approx, -compare, -tfidf, -trend, -near and -stats print text only")
+		os.Exit(2)
+	}
+	if (*bars || *svg != "") && (outFormat != wordfreq.Text || cols != 0 || *forms) {
+		fmt.Fprintln(os.Stderr, "-bars and -svg cannot be used with -format, -show or -forms")
+		os.Exit(2)
+	}
+	svgWidth, svgHeight, err := parseSize(*size)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	opts := []wordfreq.Option{wordfreq.WithTieBreak(tieBreak), wordfreq.WithWorkers(*workers)}
+	switch {
+	case *fields:
+		opts = append(opts, wordfreq.WithTokenizer(wordfreq.FieldsTokenizer))
+	case *noURLs || *noEmails:
+		// URLs and email addresses are kept whole to be told from other words
+		opts = append(opts, wordfreq.WithTokenizer(wordfreq.UnicodeTokenizer{Links: true}))
+	}
+	if *stop != "" || *stopFile != "" {
+		stopWords, err := loadStopWords(*stop, *stopFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		opts = append(opts, wordfreq.WithStopWords(stopWords))
+	}
+	filters, err := newFilters(*include, *exclude, *minLen, *maxLen, *noNum, *noURLs, *noEmails)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	opts = append(opts, wordfreq.WithFilters(filters...))
+	if *stem != "" {
+		stemmer, err := wordfreq.NewStemmer(*stem)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		opts = append(opts, wordfreq.WithNormalizer(stemmer))
+	}
+	if *chars {
+		opts = append(opts, wordfreq.WithCharNGrams(*n))
+	} else {
+		opts = append(opts, wordfreq.WithNGrams(*n))
+	}
+	// word n-grams contain spaces, so they are printed one per line
+	out := output{
+		format: outFormat,
+		cols:   cols,
+		lines:  *n > 1 && !*chars || cols != 0 || *forms || *bars,
+		forms:  *forms,
+		bars:   *bars,
+		width:  *width,
+		svg:    *svg,
+		size:   [2]int{svgWidth, svgHeight},
+	}
+	switch {
+	case *approx > 0:
+		err = runApprox(*k, *approx, flag.Args(), opts)
+	case *index != "":
+		if *forms {
+			err = errors.New("-forms is not kept by an index")
+			break
+		}
+		err = runIndex(*k, *index, *add, *merge, flag.Args(), out, opts)
+	case *trend != 0:
+		th := wordfreq.Thresholds{MinCount: *minCount, MinLogLikelihood: *minLL}
+		err = runTrends(*k, flag.Args(), wordfreq.TrendWindow{Size: *trend, Step: *step}, statistic, th, opts)
+	case *stats:
+		err = runStats(flag.Args(), opts)
+	case *near != "":
+		err = runNear(*k, flag.Args(), *near, *window, association, *minCount, opts)
+	case *tfidf:
+		err = runTFIDF(*k, flag.Args(), opts)
+	case *compare != "":
+		th := wordfreq.Thresholds{MinCount: *minCount, MinLogLikelihood: *minLL}
+		err = runCompare(*k, flag.Args(), strings.Split(*compare, ","), statistic, th, opts)
+	case flag.NArg() == 0 && !isFlagSet("k") && isTerminal(os.Stdin):
+		err = runInteractive(out, opts)
+	default:
+		err = runFiles(*k, flag.Args(), out, opts)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// output says how the most frequent words are printed.
+type output struct {
+	format wordfreq.Format
+	cols   wordfreq.Columns
+	lines  bool // a word per line rather than all of them in one line
+	forms  bool // the forms merged into every word after the columns
+	bars   bool // a bar chart of width characters
+	width  int
+	svg    string // file to write a word cloud of size pixels to instead
+	size   [2]int
+}
+
+// print prints the entries, with the forms counted by c if o.forms is set.
+func (o output) print(entries wordfreq.EntrySlice, c *wordfreq.Counter) error {
+	if o.svg != "" {
+		return writeSVG(o.svg, entries, o.size[0], o.size[1])
+	}
+	if o.bars {
+		return entries.WriteBars(os.Stdout, o.width)
+	}
+	if o.format != wordfreq.Text || !o.lines {
+		return entries.Write(os.Stdout, o.format, o.cols)
+	}
+	for _, e := range entries {
+		line := e.Format(o.cols)
+		if o.forms {
+			line += "\t" + formatForms(c.Forms(e.Word))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// writeSVG writes the entries as a word cloud of width by height pixels to the named file.
+func writeSVG(name string, entries wordfreq.EntrySlice, width, height int) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := entries.WriteSVG(f, width, height); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runInteractive asks for a line of words and K and prints the result.
+func runInteractive(out output, opts []wordfreq.Option) error {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Printf("Input a string with some words with space between it: ")
 	s, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 	k, err := wordfreq.ParseK("Input number of unique words to show: ", reader, os.Stdout)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
-	res := wordfreq.GetResultWordsSlice(s, k)
+	c := wordfreq.NewCounter(opts...)
+	c.Add(s)
 	fmt.Print("Result: ")
-	res.PrintWords(os.Stdout)
+	if out.lines {
+		fmt.Println()
+	}
+	return out.print(c.TopEntries(k), c)
+}
+
+// runFiles counts the words of the files, stdin if there are none,
+// and prints the k most frequent ones.
+func runFiles(k int, files []string, out output, opts []wordfreq.Option) error {
+	c := wordfreq.NewCounter(opts...)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := countFile(c, name); err != nil {
+			return err
+		}
+	}
+	return out.print(c.TopEntries(k), c)
+}
+
+// formatForms lists forms as "form:count" separated by spaces, the most frequent first.
+func formatForms(forms map[wordfreq.Word]int) string {
+	words := slices.SortedFunc(maps.Keys(forms), func(a, b wordfreq.Word) int {
+		if forms[a] != forms[b] {
+			return forms[b] - forms[a]
+		}
+		return strings.Compare(string(a), string(b))
+	})
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = fmt.Sprintf("%s:%d", w, forms[w])
+	}
+	return strings.Join(parts, " ")
+}
+
+// runApprox estimates the k most frequent words of the files, stdin if there are none,
+// monitoring at most capacity words, and prints them with their error bounds.
+func runApprox(k, capacity int, files []string, opts []wordfreq.Option) error {
+	s := wordfreq.NewSpaceSaving(capacity, opts...)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := countFile(s, name); err != nil {
+			return err
+		}
+	}
+	top, guaranteed := s.Top(k)
+	for _, e := range top {
+		fmt.Printf("%s\t%d\t%d\n", e.Word, e.Count, e.Error)
+	}
+	certainty := "may differ from the exact one"
+	if guaranteed {
+		certainty = "is exact"
+	}
+	fmt.Fprintf(os.Stderr, "%d words, counts exceed exact ones by at most %d, the top %d %s\n",
+		s.Total(), s.ErrorBound(), len(top), certainty)
+	return nil
+}
+
+// runCompare compares the files, stdin if there are none, with the reference files
+// and prints the k most overused and the k most underused words in them.
+func runCompare(k int, files, refs []string, by wordfreq.Statistic, th wordfreq.Thresholds, opts []wordfreq.Option) error {
+	target, reference := wordfreq.NewCounter(opts...), wordfreq.NewCounter(opts...)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := countFile(target, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range refs {
+		if err := countFile(reference, name); err != nil {
+			return err
+		}
+	}
+	overused, underused := wordfreq.Extremes(wordfreq.Compare(target, reference, by, th), k)
+	for i, group := range []struct {
+		heading string
+		words   []wordfreq.Keyness
+	}{{"overused", overused}, {"underused", underused}} {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(group.heading)
+		fmt.Println("word\ttarget\treference\tll\tchi2\tlogratio")
+		for _, w := range group.words {
+			fmt.Printf("%s\t%d\t%d\t%.2f\t%.2f\t%+.2f\n", w.Word, w.Target, w.Reference, w.LogLikelihood, w.ChiSquared, w.LogRatio)
+		}
+	}
+	return nil
+}
+
+// runStats prints the statistics of the text of the files, stdin if there are none.
+func runStats(files []string, opts []wordfreq.Option) error {
+	c := wordfreq.NewStatsCounter(opts...)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := countFile(c, name); err != nil {
+			return err
+		}
+	}
+	s := c.Stats()
+	fmt.Printf("words\t%d\n", s.Words)
+	fmt.Printf("unique words\t%d\n", s.UniqueWords)
+	fmt.Printf("hapax legomena\t%d\n", s.Hapaxes)
+	fmt.Printf("sentences\t%d\n", s.Sentences)
+	fmt.Printf("syllables\t%d\n", s.Syllables)
+	fmt.Printf("type-token ratio\t%.3f\n", s.TypeTokenRatio())
+	fmt.Printf("average word length\t%.2f\n", s.AverageWordLength())
+	fmt.Printf("average sentence length\t%.2f\n", s.AverageSentenceLength())
+	fmt.Printf("Flesch reading ease\t%.2f\n", s.FleschReadingEase())
+	fmt.Printf("Flesch-Kincaid grade\t%.2f\n", s.FleschKincaidGrade())
+	fmt.Printf("Gunning fog\t%.2f\n", s.GunningFog())
+	fmt.Printf("Coleman-Liau\t%.2f\n", s.ColemanLiau())
+	fmt.Printf("ARI\t%.2f\n", s.AutomatedReadability())
+	fmt.Printf("SMOG\t%.2f\n", s.SMOG())
+	return nil
+}
+
+// runNear counts the words of the files, stdin if there are none, occurring
+// within window words of the node word and prints the k most associated with it.
+func runNear(k int, files []string, node string, window int, by wordfreq.Association, minCount int, opts []wordfreq.Option) error {
+	c := wordfreq.NewCooccurrences(window, opts...)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := countFile(c, name); err != nil {
+			return err
+		}
+	}
+	fmt.Println("word\tnear\tcount\ttscore\tpmi\tdice")
+	for _, w := range c.Collocates(node, k, by, minCount) {
+		fmt.Printf("%s\t%d\t%d\t%.2f\t%.2f\t%.2f\n", w.Word, w.Together, w.Count, w.TScore, w.PMI, w.Dice)
+	}
+	return nil
+}
+
+// runIndex appends the files to the index file if add is set, merges the
+// comma-separated index files into it if merge is not empty, and prints its
+// k most frequent words otherwise.
+func runIndex(k int, name string, add bool, merge string, files []string, out output, opts []wordfreq.Option) error {
+	switch {
+	case add:
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+		for _, file := range files {
+			c := wordfreq.NewCounter(opts...)
+			if err := countFile(c, file); err != nil {
+				return err
+			}
+			if err := wordfreq.AppendIndex(name, file, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	case merge != "":
+		x, err := wordfreq.OpenIndex(name, opts...)
+		if errors.Is(err, fs.ErrNotExist) {
+			x, err = wordfreq.NewIndex(opts...), nil
+		}
+		if err != nil {
+			return err
+		}
+		warnIncomplete(name, x)
+		for other := range strings.SplitSeq(merge, ",") {
+			y, err := wordfreq.OpenIndex(other, opts...)
+			if err != nil {
+				return err
+			}
+			warnIncomplete(other, y)
+			x.Merge(y)
+		}
+		return x.WriteFile(name)
+	}
+	x, err := wordfreq.OpenIndex(name, opts...)
+	if err != nil {
+		return err
+	}
+	warnIncomplete(name, x)
+	return out.print(x.TopEntries(k), nil)
+}
+
+// warnIncomplete warns that the named index file ends with an unfinished block,
+// which is left out, if x read from it does.
+func warnIncomplete(name string, x *wordfreq.Index) {
+	if x.Incomplete() {
+		fmt.Fprintf(os.Stderr, "%s: an unfinished block at the end is ignored\n", name)
+	}
+}
+
+// runTrends reads timestamped lines of the files, stdin if there are none,
+// and prints the k words trending in every window of time.
+func runTrends(k int, files []string, w wordfreq.TrendWindow, by wordfreq.Statistic, th wordfreq.Thresholds, opts []wordfreq.Option) error {
+	t, err := wordfreq.NewTrends(w, k, by, th, opts...)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	fmt.Println("start\tend\tword\tcount\tprevious\tll\tlogratio")
+	skipped := 0
+	for _, name := range files {
+		err := scanFile(name, func(line string) {
+			at, text, err := wordfreq.ParseTimestamped(line)
+			if err != nil {
+				skipped++
+				return
+			}
+			printWindows(t.Add(at, text))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	printWindows(t.Flush())
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "lines without a timestamp skipped: %d\n", skipped)
+	}
+	return nil
+}
+
+// printWindows prints the trending words of the windows.
+func printWindows(windows []wordfreq.Window) {
+	for _, w := range windows {
+		start, end := w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339)
+		for _, k := range w.Trending {
+			fmt.Printf("%s\t%s\t%s\t%d\t%d\t%.2f\t%+.2f\n", start, end, k.Word, k.Target, k.Reference, k.LogLikelihood, k.LogRatio)
+		}
+	}
+}
+
+// scanFile passes the lines of the named file, "-" stands for stdin, to line.
+func scanFile(name string, line func(string)) error {
+	f := os.Stdin
+	if name != "-" {
+		var err error
+		if f, err = os.Open(name); err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// runTFIDF counts the words of every document: a file or a file in a directory,
+// and prints the k words of each with the highest TF-IDF.
+func runTFIDF(k int, paths []string, opts []wordfreq.Option) error {
+	if len(paths) == 0 {
+		return errors.New("-tfidf needs files or directories of documents")
+	}
+	c := wordfreq.NewCollection(opts...)
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := c.Add(name, f); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println("document\tword\tcount\tdf\ttfidf")
+	for _, doc := range c.Top(k) {
+		for _, t := range doc.Terms {
+			fmt.Printf("%s\t%s\t%d\t%d\t%.4f\n", doc.Name, t.Word, t.Count, t.DF, t.TFIDF)
+		}
+	}
+	return nil
+}
+
+// countFile counts the words of the named file, "-" stands for stdin.
+func countFile(c io.ReaderFrom, name string) error {
+	if name == "-" {
+		_, err := c.ReadFrom(os.Stdin)
+		return err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := c.ReadFrom(f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// loadStopWords combines the built-in lists of comma-separated languages
+// and the lists of comma-separated files.
+func loadStopWords(langs, files string) (wordfreq.StopWords, error) {
+	result := wordfreq.StopWords{}
+	for lang := range strings.SplitSeq(langs, ",") {
+		if lang == "" {
+			continue
+		}
+		s, err := wordfreq.LoadStopWords(lang)
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(s)
+	}
+	for name := range strings.SplitSeq(files, ",") {
+		if name == "" {
+			continue
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := wordfreq.ReadStopWords(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result.Merge(s)
+	}
+	return result, nil
+}
+
+// newFilters returns the filters of the words to count given on the command line.
+func newFilters(include, exclude string, minLen, maxLen int, noNum, noURLs, noEmails bool) ([]wordfreq.Filter, error) {
+	var filters []wordfreq.Filter
+	if include != "" {
+		re, err := regexp.Compile(include)
+		if err != nil {
+			return nil, fmt.Errorf("-include: %w", err)
+		}
+		filters = append(filters, wordfreq.IncludeFilter(re))
+	}
+	if exclude != "" {
+		re, err := regexp.Compile(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("-exclude: %w", err)
+		}
+		filters = append(filters, wordfreq.ExcludeFilter(re))
+	}
+	if minLen > 0 || maxLen > 0 {
+		filters = append(filters, wordfreq.LengthFilter(minLen, maxLen))
+	}
+	if noNum {
+		filters = append(filters, wordfreq.NumericFilter)
+	}
+	if noURLs {
+		filters = append(filters, wordfreq.URLFilter)
+	}
+	if noEmails {
+		filters = append(filters, wordfreq.EmailFilter)
+	}
+	return filters, nil
+}
+
+// setFlags returns the sorted names of the flags which are set.
+func setFlags(flags map[string]bool) []string {
+	var result []string
+	for name, set := range flags {
+		if set {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// isFlagSet reports whether the flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns the width of the terminal of stdout, or of COLUMNS
+// if stdout is not a terminal, 80 if it is not set either.
+func terminalWidth() int {
+	if n, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && n > 0 {
+		return n
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// parseSize parses a size given as "WIDTHxHEIGHT", e.g. 800x600.
+func parseSize(s string) (int, int, error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if !ok || errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid size %q, use WIDTHxHEIGHT, e.g. 800x600", s)
+	}
+	return width, height, nil
 }
@@ -8,6 +8,7 @@ CROSS_FLDR := slicecrossing
 LOG_FLDR := visitlog
 
 COVERAGE_REPORT := report
+FUZZ_TIME ?= 5s
 CALC_EXE := calc
 WFREQ_EXE := wfreq
 CROSS_EXE := slicecross
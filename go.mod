@@ -1,3 +1,10 @@
 module github.com/tdutanton/go_console_projects
 
 go 1.24.2
+
+require (
+	golang.org/x/term v0.35.0
+	golang.org/x/text v0.30.0
+)
+
+require golang.org/x/sys v0.36.0 // indirect
@@ -0,0 +1,132 @@
+package calculator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// DefaultResultColumn is the name of the column appended by Batch when Column is empty.
+const DefaultResultColumn = "result"
+
+// ErrMissingColumn is returned when an expression uses a variable
+// which is not a column of the input.
+var ErrMissingColumn = errors.New("missing column")
+
+// RowError describes a record of the input which could not be evaluated.
+type RowError struct {
+	Line int   // line of the input where the record starts
+	Err  error // the reason
+}
+
+// Error returns the error message for RowError.
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the reason of the error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Batch evaluates an expression for every record of CSV data. The first record
+// is a header: column names are the variables of the expression and values
+// of the referenced columns are numbers written in the locale of the options.
+type Batch struct {
+	Expr    *Expr
+	Column  string // name of the appended result column, DefaultResultColumn if empty
+	Comma   rune   // field delimiter, ',' if zero
+	Options Options
+}
+
+// Run reads CSV data from r and writes it to w with the result column appended.
+// Records which cannot be evaluated get an empty result and are reported
+// in the returned slice, processing goes on. The error is not nil only when
+// the data cannot be read or written at all, the records before it are written then.
+func (b Batch) Run(r io.Reader, w io.Writer) ([]*RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	writer := csv.NewWriter(w)
+	// the records written before a read error are kept
+	defer writer.Flush()
+	if b.Comma != 0 {
+		reader.Comma = b.Comma
+		writer.Comma = b.Comma
+	}
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(b.Expr.vars))
+	for _, name := range b.Expr.vars {
+		i := slices.Index(header, name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+		columns[name] = i
+	}
+	column := b.Column
+	if column == "" {
+		column = DefaultResultColumn
+	}
+	if err := writer.Write(append(header, column)); err != nil {
+		return nil, err
+	}
+	var rowErrors []*RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rowErrors, err
+		}
+		line, _ := reader.FieldPos(0)
+		result, err := b.evalRecord(record, columns)
+		if err != nil {
+			rowErrors = append(rowErrors, &RowError{Line: line, Err: err})
+		}
+		if err := writer.Write(append(record, result)); err != nil {
+			return rowErrors, err
+		}
+	}
+	writer.Flush()
+	return rowErrors, writer.Error()
+}
+
+// evalRecord evaluates the expression for one record and returns the formatted result.
+func (b Batch) evalRecord(record []string, columns map[string]int) (string, error) {
+	vars := make(Vars, len(columns))
+	for _, name := range b.Expr.vars {
+		i := columns[name]
+		if i >= len(record) {
+			return "", fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+		v, err := parseCell(record[i], b.Options.Locale)
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", name, err)
+		}
+		vars[name] = v
+	}
+	v, err := b.Expr.EvalInterval(vars, b.Options)
+	if err != nil {
+		return "", err
+	}
+	return b.Options.FormatInterval(v), nil
+}
+
+// parseCell parses a number written in the locale, a trailing '%' makes it a percentage.
+func parseCell(s string, l Locale) (float64, error) {
+	s = strings.TrimSpace(s)
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := l.ParseNumber(p)
+		return v / 100, err
+	}
+	return l.ParseNumber(s)
+}
@@ -0,0 +1,127 @@
+package calculator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBatch_Run(t *testing.T) {
+	tests := []struct {
+		name      string
+		locale    string
+		comma     rune
+		expr      string
+		input     string
+		expected  string
+		errLines  []int
+		expRunErr error
+	}{
+		{
+			name:     "price with tax",
+			locale:   "C",
+			expr:     "price*qty*(1+tax)",
+			input:    "item,price,qty,tax\npen,1.5,4,20%\nbook,10,1,0.1\n",
+			expected: "item,price,qty,tax,result\npen,1.5,4,20%,7.2\nbook,10,1,0.1,11\n",
+		},
+		{
+			name:     "row errors",
+			locale:   "C",
+			expr:     "a/b",
+			input:    "a,b\n1,2\n1,0\nx,1\n3\n",
+			expected: "a,b,result\n1,2,0.5\n1,0,\nx,1,\n3,\n",
+			errLines: []int{3, 4, 5},
+		},
+		{
+			name:     "decimal comma",
+			locale:   "de",
+			comma:    ';',
+			expr:     "a + b",
+			input:    "a;b\n1,5;1.000,25\n",
+			expected: "a;b;result\n1,5;1.000,25;1.001,75\n",
+		},
+		{
+			name:      "missing column",
+			locale:    "C",
+			expr:      "price*qty",
+			input:     "price,count\n1,2\n",
+			expRunErr: ErrMissingColumn,
+		},
+		{
+			name:      "malformed row",
+			locale:    "C",
+			expr:      "a*2",
+			input:     "a\n1\n2\n3\n\"\n4\n",
+			expected:  "a,result\n1,2\n2,4\n3,6\n",
+			expRunErr: csv.ErrQuote,
+		},
+		{
+			name:     "empty input",
+			locale:   "C",
+			expr:     "1",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, _ := LookupLocale(d.locale)
+			e, err := ParseExpr(d.expr, l)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			b := Batch{Expr: e, Comma: d.comma, Options: Options{Locale: l, Precision: 3}}
+			var output bytes.Buffer
+			rowErrors, err := b.Run(strings.NewReader(d.input), &output)
+			if d.expRunErr != nil {
+				if !errors.Is(err, d.expRunErr) {
+					t.Fatalf("Expected %v, got %v", d.expRunErr, err)
+				}
+				// the rows before the error are written anyway
+				if output.String() != d.expected {
+					t.Errorf("Got: %q\nWant: %q", output.String(), d.expected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.String() != d.expected {
+				t.Errorf("Got: %q\nWant: %q", output.String(), d.expected)
+			}
+			if len(rowErrors) != len(d.errLines) {
+				t.Fatalf("Expected %d row errors, got %v", len(d.errLines), rowErrors)
+			}
+			for i, e := range rowErrors {
+				if e.Line != d.errLines[i] {
+					t.Errorf("Expected error at line %d, got %v", d.errLines[i], e)
+				}
+			}
+		})
+	}
+}
+
+func TestRowError(t *testing.T) {
+	err := error(&RowError{Line: 3, Err: ErrDivisionByZero})
+	if !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Expected ErrDivisionByZero, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Errorf("Expected line prefix, got %s", err.Error())
+	}
+}
+
+func TestExpr_Vars(t *testing.T) {
+	e, err := ParseExpr("price*qty + price*tax + pmt(rate, 1, 1)", CLocale)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"price", "qty", "tax", "rate"}
+	got := e.Vars()
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
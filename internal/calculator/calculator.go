@@ -1,5 +1,6 @@
-// Package calculator provides basic arithmetic operations
-// and utilities to parse operands and operators from user input
+// Package calculator provides basic arithmetic operations,
+// utilities to parse operands and operators from user input
+// and an evaluator of arithmetic expressions with financial functions.
 package calculator
 
 import (
@@ -8,44 +9,152 @@ import (
 	"fmt"
 	"io"
 	"math"
-	"strconv"
 	"strings"
 )
 
-// operators map with available math operators
-var operators = map[rune]bool{
-	'+': true,
-	'-': true,
-	'*': true,
-	'/': true,
+// DefaultPrecision is the number of fractional digits kept after every operation
+// unless Options say otherwise.
+const DefaultPrecision = 3
+
+// ErrDivisionByZero is returned when the right operand of a division is zero.
+var ErrDivisionByZero = errors.New("unfortunately you can't divide by zero :-(")
+
+// Options holds the settings shared by every calculator mode.
+type Options struct {
+	Locale    Locale // how numbers are read and printed
+	Precision int    // fractional digits kept after every operation, -1 keeps full precision
+	Interval  bool   // interval mode: enclose rounding errors of expressions in intervals
+}
+
+// DefaultOptions returns options of the classic calculator:
+// the C locale and DefaultPrecision.
+func DefaultOptions() Options {
+	return Options{Locale: CLocale, Precision: DefaultPrecision}
+}
+
+// round rounds v to the precision of the options.
+func (o Options) round(v float64) float64 {
+	return roundTo(v, o.Precision)
+}
+
+// Format formats v in the locale of the options with the smallest number
+// of fractional digits necessary, which never exceeds the precision for rounded values.
+func (o Options) Format(v float64) string {
+	return o.Locale.FormatNumber(v, -1)
+}
+
+// FormatInterval formats a number like Format and an interval as [lo, hi]
+// with bounds separated like function arguments in the locale.
+func (o Options) FormatInterval(v Interval) string {
+	if v.IsPoint() {
+		return o.Format(v.Lo)
+	}
+	return "[" + o.Format(v.Lo) + string(o.Locale.ArgSeparator()) + " " + o.Format(v.Hi) + "]"
+}
+
+// Operate applies operator r to left and right and rounds the result to the precision of the options.
+func (o Options) Operate(left float64, r rune, right float64) (float64, error) {
+	op, ok := operators[r]
+	if !ok {
+		return 0, errors.New("unknown operation")
+	}
+	result, err := op.apply(left, right)
+	if err != nil {
+		return 0, err
+	}
+	return o.round(result), nil
+}
+
+// OperateInterval applies operator r to intervals left and right and rounds
+// the result outward to the precision of the options. The result is computed
+// exactly and its rounded decimal bounds are given as the nearest floating point numbers.
+func (o Options) OperateInterval(left Interval, r rune, right Interval) (Interval, error) {
+	op, ok := operators[r]
+	if !ok {
+		return Interval{}, errors.New("unknown operation")
+	}
+	result, err := op.applyValue(floatValue(left), floatValue(right))
+	if err != nil {
+		return Interval{}, err
+	}
+	return result.result(o.Precision), nil
+}
+
+// operator describes a binary operator: its precedence in expressions
+// and the exact arithmetic behind it for numbers and intervals, without rounding.
+type operator struct {
+	prec       int
+	apply      func(left, right float64) (float64, error)
+	applyValue func(left, right value) (value, error)
+}
+
+// operators registry with available math operators shared by every calculator mode
+var operators = map[rune]operator{
+	'+': {
+		prec:       1,
+		apply:      func(left, right float64) (float64, error) { return left + right, nil },
+		applyValue: func(left, right value) (value, error) { return addValues(left, right), nil },
+	},
+	'-': {
+		prec:       1,
+		apply:      func(left, right float64) (float64, error) { return left - right, nil },
+		applyValue: func(left, right value) (value, error) { return addValues(left, right.neg()), nil },
+	},
+	'*': {
+		prec:       2,
+		apply:      func(left, right float64) (float64, error) { return left * right, nil },
+		applyValue: func(left, right value) (value, error) { return mulValues(left, right), nil },
+	},
+	'/': {
+		prec:       2,
+		apply:      quotient,
+		applyValue: divValues,
+	},
+}
+
+// isOperator reports whether r is in the operators registry
+func isOperator(r rune) bool {
+	_, ok := operators[r]
+	return ok
+}
+
+// quotient left / right without rounding
+// left / 0 -> return error
+func quotient(left, right float64) (float64, error) {
+	if right == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return left / right, nil
+}
+
+// roundTo rounds v to prec fractional digits, negative prec leaves v as is
+func roundTo(v float64, prec int) float64 {
+	if prec < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return v
+	}
+	p := math.Pow(10, float64(prec))
+	return math.Round(v*p) / p
 }
 
 // add left + right, error = nil
 func add(left, right float64) (float64, error) {
-	result := left + right
-	return math.Round(result*1000) / 1000, nil
+	return DefaultOptions().Operate(left, '+', right)
 }
 
 // sub left - right, error = nil
 func sub(left, right float64) (float64, error) {
-	result := left - right
-	return math.Round(result*1000) / 1000, nil
+	return DefaultOptions().Operate(left, '-', right)
 }
 
 // mult left * right, error = nil
 func mult(left, right float64) (float64, error) {
-	result := left * right
-	return math.Round(result*1000) / 1000, nil
+	return DefaultOptions().Operate(left, '*', right)
 }
 
 // div left / right
 // left / 0 -> return error
 func div(left, right float64) (float64, error) {
-	if right == 0 {
-		return 0, errors.New("unfortunately you can't divide by zero :-(")
-	}
-	result := left / right
-	return math.Round(result*1000) / 1000, nil
+	return DefaultOptions().Operate(left, '/', right)
 }
 
 // stringLength length of string
@@ -84,7 +193,7 @@ func ParseOperator(s string, reader *bufio.Reader, writer io.Writer) (rune, erro
 			return 0, errors.New("input error")
 		}
 		input = strings.TrimSpace(input)
-		if stringLength(input) != 1 || !operators[rune(input[0])] {
+		if stringLength(input) != 1 || !isOperator(rune(input[0])) {
 			fmt.Fprint(writer, "Invalid input. Please give me correct operator (+, -, *, /): ")
 			continue
 		}
@@ -95,14 +204,23 @@ func ParseOperator(s string, reader *bufio.Reader, writer io.Writer) (rune, erro
 // ParseOperand get operand (left or right) from string.
 // String 's' is the prompt message shown to the user in the command line
 func ParseOperand(s string, reader *bufio.Reader, writer io.Writer) (float64, error) {
+	return ParseLocaleOperand(s, CLocale, reader, writer)
+}
+
+// ParseLocaleOperand get operand (left or right) written in the locale 'l' from string.
+// String 's' is the prompt message shown to the user in the command line
+func ParseLocaleOperand(s string, l Locale, reader *bufio.Reader, writer io.Writer) (float64, error) {
 	fmt.Fprint(writer, s)
 	for {
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return 0, errors.New("input error")
 		}
-		input = strings.TrimSpace(input)
-		v, err := strconv.ParseFloat(input, 64)
+		v, err := l.ParseNumber(input)
+		if errors.Is(err, ErrAmbiguousNumber) {
+			fmt.Fprint(writer, "Ambiguous input. Please write the number without grouping or with a decimal part: ")
+			continue
+		}
 		if err != nil {
 			fmt.Fprint(writer, "Invalid input. Please give me correct operand: ")
 			continue
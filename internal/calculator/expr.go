@@ -0,0 +1,620 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errors returned while parsing and evaluating expressions.
+var (
+	ErrSyntax          = errors.New("syntax error")              // Returned for malformed expressions.
+	ErrUnknownFunction = errors.New("unknown function")          // Returned for calls of unregistered functions.
+	ErrArgCount        = errors.New("wrong number of arguments") // Returned when a call does not match the function arity.
+	ErrDomain          = errors.New("argument out of domain")    // Returned when a function is undefined for its arguments.
+	ErrUnknownVariable = errors.New("unknown variable")          // Returned when a variable has no value.
+	ErrNotPoint        = errors.New("result is an interval")     // Returned by EvalVars for interval results.
+)
+
+// maxIntervalArgs limits the number of interval arguments of a function call:
+// the function is evaluated at every combination of their bounds.
+const maxIntervalArgs = 10
+
+// Vars maps variable names used in expressions to their values.
+type Vars map[string]float64
+
+// tokenKind is the kind of a lexical token of an expression.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokSeparator
+	tokLBracket
+	tokRBracket
+)
+
+// token is a single lexical token with its byte offset in the source.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	num  numberNode
+	op   rune
+}
+
+// lexer splits an expression into tokens. Numbers are written in the locale,
+// function arguments are separated by ';' or, when the locale does not use
+// a decimal comma, by ','.
+type lexer struct {
+	src    string
+	pos    int
+	locale Locale
+}
+
+// ArgSeparator returns the rune separating function arguments in expressions
+// written in the locale: ',' or ';' for locales with a decimal comma.
+func (l Locale) ArgSeparator() rune {
+	if l.Decimal == ',' {
+		return ';'
+	}
+	return ','
+}
+
+// peekRune returns the rune at byte offset i of the source or utf8.RuneError at the end.
+func (lx *lexer) peekRune(i int) (rune, int) {
+	if i >= len(lx.src) {
+		return utf8.RuneError, 0
+	}
+	return utf8.DecodeRuneInString(lx.src[i:])
+}
+
+// isDigitAt reports whether there is an ASCII digit at byte offset i.
+func (lx *lexer) isDigitAt(i int) bool {
+	r, _ := lx.peekRune(i)
+	return r >= '0' && r <= '9'
+}
+
+// next returns the next token of the source.
+func (lx *lexer) next() (token, error) {
+	for lx.pos < len(lx.src) {
+		r, size := lx.peekRune(lx.pos)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		lx.pos += size
+	}
+	start := lx.pos
+	if start >= len(lx.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	r, size := lx.peekRune(start)
+	switch {
+	case r >= '0' && r <= '9' || r == lx.locale.Decimal && lx.isDigitAt(start+size):
+		return lx.number()
+	case unicode.IsLetter(r) || r == '_':
+		for lx.pos < len(lx.src) {
+			r, size := lx.peekRune(lx.pos)
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				break
+			}
+			lx.pos += size
+		}
+		return token{kind: tokIdent, text: lx.src[start:lx.pos], pos: start}, nil
+	case r == '(':
+		lx.pos += size
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case r == ')':
+		lx.pos += size
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case r == '[':
+		lx.pos += size
+		return token{kind: tokLBracket, text: "[", pos: start}, nil
+	case r == ']':
+		lx.pos += size
+		return token{kind: tokRBracket, text: "]", pos: start}, nil
+	case r == ';' || r == lx.locale.ArgSeparator():
+		lx.pos += size
+		return token{kind: tokSeparator, text: string(r), pos: start}, nil
+	case isOperator(r):
+		lx.pos += size
+		return token{kind: tokOperator, text: string(r), pos: start, op: r}, nil
+	}
+	return token{}, fmt.Errorf("%w at %d: unexpected %q", ErrSyntax, start, r)
+}
+
+// number scans a number literal with an optional exponent and percent sign.
+// Grouping separators are part of a number only when they are not the argument
+// separator, and whitespace ones only when a digit follows them.
+func (lx *lexer) number() (token, error) {
+	start := lx.pos
+	sep := lx.locale.ArgSeparator()
+	for lx.pos < len(lx.src) {
+		r, size := lx.peekRune(lx.pos)
+		isDigit := r >= '0' && r <= '9'
+		isGroup := lx.locale.isGroup(r) && r != sep && (!unicode.IsSpace(r) || lx.isDigitAt(lx.pos+size))
+		if !isDigit && r != lx.locale.Decimal && !isGroup {
+			break
+		}
+		lx.pos += size
+	}
+	if r, _ := lx.peekRune(lx.pos); r == 'e' || r == 'E' {
+		i := lx.pos + 1
+		if r, _ := lx.peekRune(i); r == '+' || r == '-' {
+			i++
+		}
+		if lx.isDigitAt(i) {
+			for lx.pos = i; lx.isDigitAt(lx.pos); lx.pos++ {
+			}
+		}
+	}
+	c, err := lx.locale.canonical(lx.src[start:lx.pos])
+	if err == nil {
+		_, err = strconv.ParseFloat(c, 64)
+	}
+	if err != nil {
+		return token{}, fmt.Errorf("%w at %d: %w", ErrSyntax, start, err)
+	}
+	percent := false
+	if r, _ := lx.peekRune(lx.pos); r == '%' {
+		lx.pos++
+		percent = true
+	}
+	n, err := newNumberNode(c, percent)
+	if err != nil {
+		return token{}, fmt.Errorf("%w at %d: %w", ErrSyntax, start, err)
+	}
+	return token{kind: tokNumber, text: lx.src[start:lx.pos], pos: start, num: n}, nil
+}
+
+// env is the context of an evaluation.
+type env struct {
+	vars     Vars
+	interval bool // enclose literals and results of operations in intervals
+	prec     int  // fractional digits results of operations are rounded to in the interval mode
+}
+
+// node is an element of the expression tree. Values are intervals, numbers
+// are degenerate ones.
+type node interface {
+	eval(e env) (value, error)
+}
+
+// env returns the context of an evaluation with the given variables.
+func (o Options) env(vars Vars) env {
+	return env{vars: vars, interval: o.Interval, prec: o.Precision}
+}
+
+// roundResult rounds a result to the precision of the options:
+// numbers to the nearest value, intervals outward.
+func (o Options) roundResult(v value) Interval {
+	if !o.Interval && v.isPoint() {
+		return Point(o.round(v.number()))
+	}
+	return v.result(o.Precision)
+}
+
+// numberNode is a literal with its exact value. Outside the interval mode
+// numbers are combined as the nearest floating point numbers.
+type numberNode struct {
+	v value
+}
+
+// newNumberNode returns a literal for the number in strconv syntax,
+// divided by 100 when percent is set.
+func newNumberNode(s string, percent bool) (numberNode, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return numberNode{}, err
+	}
+	if percent {
+		v /= 100
+	}
+	exact, err := literalValue(s, percent)
+	if err != nil {
+		exact = floatValue(Point(v))
+	}
+	return numberNode{v: exact}, nil
+}
+
+func (n numberNode) eval(env) (value, error) {
+	return n.v, nil
+}
+
+// intervalNode is an interval literal [lo, hi] with bounds given by expressions.
+type intervalNode struct {
+	lo, hi node
+}
+
+func (n intervalNode) eval(e env) (value, error) {
+	lo, err := n.lo.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	hi, err := n.hi.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	iv, err := NewInterval(lo.iv.Lo, hi.iv.Hi)
+	if err != nil {
+		return value{}, err
+	}
+	v := value{iv: iv, lo: lo.lo, hi: hi.hi}
+	if l, h := v.bounds(); l != nil && h != nil && l.Cmp(h) > 0 {
+		return value{}, fmt.Errorf("%w: [%s, %s]", ErrEmptyInterval, l.FloatString(3), h.FloatString(3))
+	}
+	return v, nil
+}
+
+// varNode is a reference to a variable.
+type varNode struct {
+	name string
+}
+
+func (n varNode) eval(e env) (value, error) {
+	v, ok := e.vars[n.name]
+	if !ok {
+		return value{}, fmt.Errorf("%w: %s", ErrUnknownVariable, n.name)
+	}
+	return floatValue(Point(v)), nil
+}
+
+// negNode is a unary minus.
+type negNode struct {
+	x node
+}
+
+func (n negNode) eval(e env) (value, error) {
+	v, err := n.x.eval(e)
+	return v.neg(), err
+}
+
+// binaryNode is an application of an operator from the registry.
+// Numbers are combined with plain arithmetic unless the interval mode is on.
+type binaryNode struct {
+	op          rune
+	left, right node
+}
+
+func (n binaryNode) eval(e env) (value, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	right, err := n.right.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	return e.operate(n.op, left, right)
+}
+
+// operate applies operator r from the registry: numbers are combined with
+// plain floating point arithmetic unless the interval mode is on,
+// intervals with exact arithmetic.
+func (e env) operate(r rune, left, right value) (value, error) {
+	op := operators[r]
+	if !e.interval && left.isPoint() && right.isPoint() {
+		v, err := op.apply(left.number(), right.number())
+		return floatValue(Point(v)), err
+	}
+	v, err := op.applyValue(left, right)
+	if err != nil || !e.interval {
+		return v, err
+	}
+	return v.round(e.prec), nil
+}
+
+// callNode is a call of a function from the registry.
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n callNode) eval(e env) (value, error) {
+	args := make([]value, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(e)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v
+	}
+	return e.call(n.name, n.fn, args)
+}
+
+// call evaluates function fn at every combination of bounds of interval
+// arguments and returns the smallest interval holding the results, which
+// encloses the exact range as the function is monotonic in each of them.
+// Intervals for other arguments are rejected with ErrDomain.
+// Function results themselves are not widened.
+func (e env) call(name string, fn function, args []value) (value, error) {
+	var wide []int // indices of arguments which are not numbers
+	for i, a := range args {
+		if a.isPoint() {
+			continue
+		}
+		if fn.monotonic == nil || !fn.monotonic(i) {
+			return value{}, fmt.Errorf("%s: %w: argument %d must be a number, not an interval", name, ErrDomain, i+1)
+		}
+		wide = append(wide, i)
+	}
+	if len(wide) > maxIntervalArgs {
+		return value{}, fmt.Errorf("%s: %w: at most %d interval arguments", name, ErrDomain, maxIntervalArgs)
+	}
+	point := make([]float64, len(args))
+	result := Interval{math.Inf(1), math.Inf(-1)}
+	for mask := 0; mask < 1<<len(wide); mask++ {
+		for i, a := range args {
+			point[i] = a.number()
+		}
+		for bit, i := range wide {
+			point[i] = args[i].iv.Lo
+			if mask&(1<<bit) != 0 {
+				point[i] = args[i].iv.Hi
+			}
+		}
+		v, err := fn.apply(point)
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %w", name, err)
+		}
+		result.Lo, result.Hi = math.Min(result.Lo, v), math.Max(result.Hi, v)
+	}
+	if e.interval {
+		return floatValue(result).round(e.prec), nil
+	}
+	return floatValue(result), nil
+}
+
+// parser builds an expression tree from tokens by precedence climbing
+// over the operators registry.
+type parser struct {
+	lex  *lexer
+	tok  token
+	vars []string
+}
+
+// advance reads the next token.
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+// unexpected returns a syntax error for the current token.
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	return fmt.Errorf("%w at %d: unexpected %q", ErrSyntax, p.tok.pos, p.tok.text)
+}
+
+// parseBinary parses operands joined by operators with precedence of at least minPrec.
+func (p *parser) parseBinary(minPrec int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOperator && operators[p.tok.op].prec >= minPrec {
+		op := p.tok.op
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseBinary(operators[op].prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses an operand with optional leading signs.
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokOperator && (p.tok.op == '-' || p.tok.op == '+') {
+		neg := p.tok.op == '-'
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary()
+		if err != nil || !neg {
+			return x, err
+		}
+		return negNode{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a literal, a variable, a function call, an interval
+// or a parenthesized expression.
+func (p *parser) parsePrimary() (node, error) {
+	switch p.tok.kind {
+	case tokLBracket:
+		return p.parseInterval()
+	case tokNumber:
+		n := p.tok.num
+		return n, p.advance()
+	case tokIdent:
+		return p.parseIdent()
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.unexpected()
+		}
+		return x, p.advance()
+	}
+	return nil, p.unexpected()
+}
+
+// parseInterval parses an interval literal [lo, hi] with bounds separated
+// like function arguments.
+func (p *parser) parseInterval() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	lo, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokSeparator {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	hi, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokRBracket {
+		return nil, p.unexpected()
+	}
+	return intervalNode{lo: lo, hi: hi}, p.advance()
+}
+
+// parseIdent parses a variable or, if a parenthesis follows the name,
+// a function call and checks the number of its arguments.
+func (p *parser) parseIdent() (node, error) {
+	ident := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokLParen {
+		if !slices.Contains(p.vars, ident) {
+			p.vars = append(p.vars, ident)
+		}
+		return varNode{ident}, nil
+	}
+	name := strings.ToLower(ident)
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFunction, ident)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	call := callNode{name: name, fn: fn}
+	for p.tok.kind != tokRParen {
+		if len(call.args) > 0 {
+			if p.tok.kind != tokSeparator {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	if len(call.args) < fn.minArgs || fn.maxArgs >= 0 && len(call.args) > fn.maxArgs {
+		return nil, fmt.Errorf("%w: %s takes %s", ErrArgCount, name, fn.arity())
+	}
+	return call, p.advance()
+}
+
+// Expr is a parsed arithmetic expression which may be evaluated many times.
+type Expr struct {
+	root node
+	vars []string
+}
+
+// ParseExpr parses an infix expression with numbers written in the locale.
+//
+// Expressions consist of numbers, percentages ("5%" is 0.05), the operators
+// + - * / with the usual precedence, parentheses and calls of functions
+// such as pmt(5%/12, 360, 200000). Other names are variables: their values
+// are given to EvalVars. Intervals are written as [1.9, 2.1].
+func ParseExpr(s string, l Locale) (*Expr, error) {
+	p := &parser{lex: &lexer{src: s, locale: l}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.unexpected()
+	}
+	return &Expr{root: root, vars: p.vars}, nil
+}
+
+// Vars returns the names of variables used in the expression in order of appearance.
+func (e *Expr) Vars() []string {
+	return slices.Clone(e.vars)
+}
+
+// Eval evaluates the expression without variables.
+func (e *Expr) Eval(o Options) (float64, error) {
+	return e.EvalVars(nil, o)
+}
+
+// EvalVars evaluates the expression with the given variables. It returns
+// ErrNotPoint if the result is an interval, see EvalInterval.
+func (e *Expr) EvalVars(vars Vars, o Options) (float64, error) {
+	v, err := e.EvalInterval(vars, o)
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsPoint() {
+		return 0, fmt.Errorf("%w: %s", ErrNotPoint, o.FormatInterval(v))
+	}
+	return v.Lo, nil
+}
+
+// EvalInterval evaluates the expression with the given variables.
+// Intermediate results are kept at full precision, only the result is rounded
+// to the precision of the options: numbers to the nearest value, intervals outward.
+//
+// Operations on intervals are exact: literals keep their decimal values, so
+// [1.9, 2.1] * [2.9, 3.1] is [5.51, 6.51]. In the interval mode of the options
+// every operation result is rounded outward to the precision, the way
+// the classic calculator rounds every operation, so the width of the result
+// shows the error this rounding introduces.
+func (e *Expr) EvalInterval(vars Vars, o Options) (Interval, error) {
+	v, err := e.root.eval(o.env(vars))
+	if err != nil {
+		return Interval{}, err
+	}
+	return o.roundResult(v), nil
+}
+
+// Evaluate parses the expression s in the locale of the options and evaluates it.
+func Evaluate(s string, o Options) (float64, error) {
+	e, err := ParseExpr(s, o.Locale)
+	if err != nil {
+		return 0, err
+	}
+	return e.Eval(o)
+}
+
+// EvaluateInterval parses the expression s in the locale of the options
+// and evaluates it to an interval, see Expr.EvalInterval.
+func EvaluateInterval(s string, o Options) (Interval, error) {
+	e, err := ParseExpr(s, o.Locale)
+	if err != nil {
+		return Interval{}, err
+	}
+	return e.EvalInterval(nil, o)
+}
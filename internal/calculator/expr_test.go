@@ -0,0 +1,99 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// ExampleEvaluate
+func ExampleEvaluate() {
+	o := Options{Locale: CLocale, Precision: 2}
+	result, _ := Evaluate("pmt(5%/12, 360, 200000)", o)
+	fmt.Println(o.Format(result))
+	// Output: -1073.64
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		locale    string
+		precision int
+		input     string
+		expected  float64
+		err       error
+	}{
+		{"single number", "C", 3, "42", 42, nil},
+		{"precedence", "C", 3, "2 + 3 * 4", 14, nil},
+		{"left associative", "C", 3, "10 - 4 - 3", 3, nil},
+		{"division chain", "C", 3, "24 / 4 / 3", 2, nil},
+		{"parentheses", "C", 3, "(2 + 3) * 4", 20, nil},
+		{"unary minus", "C", 3, "-2 * -(3 + 1)", 8, nil},
+		{"percent literal", "C", 3, "200 * 15%", 30, nil},
+		{"exponent", "C", 3, "1.5e3 + 1", 1501, nil},
+		{"rounded result", "C", 3, "1 / 3", 0.333, nil},
+		{"precision", "C", 5, "1 / 3", 0.33333, nil},
+		{"full precision", "C", -1, "1 / 4", 0.25, nil},
+		{"exact intermediates", "C", 2, "1 / 3 * 3", 1, nil},
+		{"decimal comma", "de", 2, "3,5 * 2", 7, nil},
+		{"grouping", "de", 2, "1.000,5 + 1", 1001.5, nil},
+		{"semicolon arguments", "de", 2, "compound(1.000,0; 5%; 1)", 1050, nil},
+		{"space grouping", "ru", 2, "1 000 + 2", 1002, nil},
+		{"function name case", "C", 2, "PMT(0, 10, 1000)", -100, nil},
+		{"division by zero", "C", 3, "1 / (2 - 2)", 0, ErrDivisionByZero},
+		{"empty", "C", 3, "", 0, ErrSyntax},
+		{"dangling operator", "C", 3, "2 +", 0, ErrSyntax},
+		{"unbalanced", "C", 3, "(2 + 3", 0, ErrSyntax},
+		{"extra token", "C", 3, "2 3", 0, ErrSyntax},
+		{"bad character", "C", 3, "2 & 3", 0, ErrSyntax},
+		{"ambiguous number", "de", 3, "1.234 + 1", 0, ErrAmbiguousNumber},
+		{"unknown function", "C", 3, "foo(1)", 0, ErrUnknownFunction},
+		{"wrong arity", "C", 3, "pmt(1, 2)", 0, ErrArgCount},
+		{"unknown variable", "C", 3, "pmt + 1", 0, ErrUnknownVariable},
+		{"comma in en arguments", "en", 2, "npv(10%,100,200)", 256.2, nil},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, err := LookupLocale(d.locale)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := Evaluate(d.input, Options{Locale: l, Precision: d.precision})
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %f, got %f", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpr_EvalTwice(t *testing.T) {
+	e, err := ParseExpr("2 * (3 + 4)", CLocale)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if got, _ := e.Eval(DefaultOptions()); got != 14 {
+			t.Errorf("Expected 14, got %f", got)
+		}
+	}
+}
+
+func TestOptions_Operate(t *testing.T) {
+	o := Options{Locale: CLocale, Precision: 1}
+	if got, _ := o.Operate(1, '/', 3); got != 0.3 {
+		t.Errorf("Expected 0.3, got %f", got)
+	}
+	if _, err := o.Operate(1, '%', 3); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -0,0 +1,219 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// function describes a function callable from expressions: the number of
+// arguments it takes, the exact arithmetic behind it, without rounding,
+// and the arguments it may take intervals for.
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 means any number of arguments
+	apply            func(args []float64) (float64, error)
+	// monotonic reports whether the function is monotonic in argument i
+	// whatever the other arguments are, nil means in none of them
+	monotonic func(i int) bool
+}
+
+// allArgs reports that a function is monotonic in every argument.
+func allArgs(int) bool {
+	return true
+}
+
+// argsFrom returns a report that a function is monotonic in arguments from first on.
+func argsFrom(first int) func(int) bool {
+	return func(i int) bool {
+		return i >= first
+	}
+}
+
+// arity describes the number of arguments of the function for error messages.
+func (f function) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return "at least " + strconv.Itoa(f.minArgs) + " arguments"
+	case f.minArgs == f.maxArgs:
+		return strconv.Itoa(f.minArgs) + " arguments"
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+// functions registry with available functions shared by every calculator mode.
+// Financial functions follow spreadsheet conventions: rates are per period
+// and money paid out is negative. Depending on the signs of the cash flows
+// the values of fv, pv, pmt and npv may rise and fall with the rate, and pmt
+// also with the number of periods, so these take numbers for them.
+var functions = map[string]function{
+	"compound": {3, 4, compound, allArgs},
+	"fv":       {3, 5, fv, argsFrom(1)},
+	"pv":       {3, 5, pv, argsFrom(1)},
+	"pmt":      {3, 5, pmt, argsFrom(2)},
+	"npv":      {2, -1, npv, argsFrom(1)},
+	"irr":      {2, -1, irr, nil},
+}
+
+// optArg returns args[i] or def if the optional argument is omitted.
+func optArg(args []float64, i int, def float64) float64 {
+	if i < len(args) {
+		return args[i]
+	}
+	return def
+}
+
+// paymentType returns the optional "type" argument: 0 for payments at the end
+// of a period, 1 for payments at the beginning.
+func paymentType(args []float64, i int) (float64, error) {
+	t := optArg(args, i, 0)
+	if t != 0 && t != 1 {
+		return 0, fmt.Errorf("%w: payment type must be 0 or 1", ErrDomain)
+	}
+	return t, nil
+}
+
+// compound(principal, rate, years[, periods]) returns the principal with
+// interest compounded periods times a year (once by default) at the yearly rate.
+func compound(args []float64) (float64, error) {
+	principal, rate, years := args[0], args[1], args[2]
+	periods := optArg(args, 3, 1)
+	if periods <= 0 {
+		return 0, fmt.Errorf("%w: periods must be positive", ErrDomain)
+	}
+	if 1+rate/periods <= 0 {
+		return 0, fmt.Errorf("%w: rate must be greater than -100%% per period", ErrDomain)
+	}
+	return principal * math.Pow(1+rate/periods, periods*years), nil
+}
+
+// fv(rate, nper, pmt[, pv[, type]]) returns the future value of an investment.
+func fv(args []float64) (float64, error) {
+	rate, nper, payment := args[0], args[1], args[2]
+	present := optArg(args, 3, 0)
+	t, err := paymentType(args, 4)
+	if err != nil {
+		return 0, err
+	}
+	if 1+rate <= 0 {
+		return 0, fmt.Errorf("%w: rate must be greater than -100%%", ErrDomain)
+	}
+	if rate == 0 {
+		return -(present + payment*nper), nil
+	}
+	growth := math.Pow(1+rate, nper)
+	return -(present*growth + payment*(1+rate*t)*(growth-1)/rate), nil
+}
+
+// pv(rate, nper, pmt[, fv[, type]]) returns the present value of an investment.
+func pv(args []float64) (float64, error) {
+	rate, nper, payment := args[0], args[1], args[2]
+	future := optArg(args, 3, 0)
+	t, err := paymentType(args, 4)
+	if err != nil {
+		return 0, err
+	}
+	if 1+rate <= 0 {
+		return 0, fmt.Errorf("%w: rate must be greater than -100%%", ErrDomain)
+	}
+	if rate == 0 {
+		return -(future + payment*nper), nil
+	}
+	growth := math.Pow(1+rate, nper)
+	return -(future + payment*(1+rate*t)*(growth-1)/rate) / growth, nil
+}
+
+// pmt(rate, nper, pv[, fv[, type]]) returns the periodic payment of a loan.
+func pmt(args []float64) (float64, error) {
+	rate, nper, present := args[0], args[1], args[2]
+	future := optArg(args, 3, 0)
+	t, err := paymentType(args, 4)
+	if err != nil {
+		return 0, err
+	}
+	if nper == 0 {
+		return 0, fmt.Errorf("%w: number of periods must not be zero", ErrDomain)
+	}
+	if 1+rate <= 0 {
+		return 0, fmt.Errorf("%w: rate must be greater than -100%%", ErrDomain)
+	}
+	if rate == 0 {
+		return -(present + future) / nper, nil
+	}
+	growth := math.Pow(1+rate, nper)
+	return -(present*growth + future) * rate / ((1 + rate*t) * (growth - 1)), nil
+}
+
+// npv(rate, value1, value2, ...) returns the net present value of cash flows
+// received at the end of consecutive periods.
+func npv(args []float64) (float64, error) {
+	rate := args[0]
+	if rate == -1 {
+		return 0, fmt.Errorf("%w: rate must not be -100%%", ErrDomain)
+	}
+	result := 0.0
+	for i, v := range args[1:] {
+		result += v / math.Pow(1+rate, float64(i+1))
+	}
+	return result, nil
+}
+
+// presentValue returns the value of cash flows at period 0 discounted at rate
+// and its derivative by rate.
+func presentValue(flows []float64, rate float64) (float64, float64) {
+	value, deriv := 0.0, 0.0
+	for i, v := range flows {
+		value += v / math.Pow(1+rate, float64(i))
+		deriv -= float64(i) * v / math.Pow(1+rate, float64(i+1))
+	}
+	return value, deriv
+}
+
+// irr(value0, value1, ...) returns the internal rate of return: the rate at
+// which the present value of the cash flows is zero. Newton's method is tried
+// first and bisection is used when it does not converge.
+func irr(flows []float64) (float64, error) {
+	hasPositive, hasNegative := false, false
+	for _, v := range flows {
+		hasPositive = hasPositive || v > 0
+		hasNegative = hasNegative || v < 0
+	}
+	if !hasPositive || !hasNegative {
+		return 0, fmt.Errorf("%w: cash flows must change sign", ErrDomain)
+	}
+	const eps = 1e-12
+	rate := 0.1
+	for range 100 {
+		value, deriv := presentValue(flows, rate)
+		if deriv == 0 {
+			break
+		}
+		next := rate - value/deriv
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < eps {
+			return next, nil
+		}
+		rate = next
+	}
+	lo, hi := -1+1e-9, 1.0
+	fLo, _ := presentValue(flows, lo)
+	fHi, _ := presentValue(flows, hi)
+	for fLo*fHi > 0 && hi < 1e9 {
+		hi *= 2
+		fHi, _ = presentValue(flows, hi)
+	}
+	if fLo*fHi > 0 {
+		return 0, fmt.Errorf("%w: no rate of return found", ErrDomain)
+	}
+	for i := 0; i < 200 && hi-lo > eps; i++ {
+		mid := (lo + hi) / 2
+		fMid, _ := presentValue(flows, mid)
+		if fLo*fMid <= 0 {
+			hi = mid
+		} else {
+			lo, fLo = mid, fMid
+		}
+	}
+	return (lo + hi) / 2, nil
+}
@@ -0,0 +1,86 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFinancialFunctions(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		precision int
+		expected  float64
+		err       error
+	}{
+		{"pmt mortgage", "pmt(5%/12, 360, 200000)", 2, -1073.64, nil},
+		{"pmt zero rate", "pmt(0, 12, 1200)", 2, -100, nil},
+		{"pmt zero periods", "pmt(5%, 0, 1000)", 2, 0, ErrDomain},
+		{"pmt in advance", "pmt(10%, 2, 1000, 0, 1)", 2, -523.81, nil},
+		{"fv savings", "fv(6%/12, 120, -100)", 2, 16387.93, nil},
+		{"fv zero rate", "fv(0, 10, -100, -1000)", 2, 2000, nil},
+		{"pv annuity", "pv(8%, 20, 500)", 2, -4909.07, nil},
+		{"pv zero rate", "pv(0, 10, -100)", 2, 1000, nil},
+		{"pv bad type", "pv(8%, 20, 500, 0, 2)", 2, 0, ErrDomain},
+		{"compound yearly", "compound(1000, 5%, 2)", 2, 1102.5, nil},
+		{"compound monthly", "compound(1000, 5%, 10, 12)", 2, 1647.01, nil},
+		{"compound bad periods", "compound(1000, 5%, 10, 0)", 2, 0, ErrDomain},
+		{"compound negative base", "compound(100, -300%, 1.5)", 2, 0, ErrDomain},
+		{"compound rate of -100% per period", "compound(100, -1200%, 1, 12)", 2, 0, ErrDomain},
+		{"npv", "npv(10%, -10000, 3000, 4200, 6800)", 2, 1188.44, nil},
+		{"npv bad rate", "npv(-100%, 1, 2)", 2, 0, ErrDomain},
+		{"fv rate -100%", "fv(-100%, -2, 100)", 2, 0, ErrDomain},
+		{"pv rate below -100%", "pv(-150%, 3, 100)", 2, 0, ErrDomain},
+		{"pmt rate -100%", "pmt(-100%, -1, 1000)", 2, 0, ErrDomain},
+		{"irr", "irr(-70000, 12000, 15000, 18000, 21000, 26000)", 4, 0.0866, nil},
+		{"irr negative", "irr(-100, 50, 40)", 4, -0.0699, nil},
+		{"irr no sign change", "irr(100, 200)", 4, 0, ErrDomain},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := Evaluate(d.input, Options{Locale: CLocale, Precision: d.precision})
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %f, got %f", d.expected, got)
+			}
+		})
+	}
+}
+
+func Test_irrRoot(t *testing.T) {
+	flows := []float64{-1000, 100, 100, 1100}
+	rate, err := irr(flows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, _ := presentValue(flows, rate); math.Abs(v) > 1e-6 {
+		t.Errorf("Expected zero present value at %f, got %f", rate, v)
+	}
+}
+
+func Test_functionArity(t *testing.T) {
+	tests := []struct {
+		fn       function
+		expected string
+	}{
+		{function{2, -1, nil, nil}, "at least 2 arguments"},
+		{function{3, 3, nil, nil}, "3 arguments"},
+		{function{3, 5, nil, nil}, "3 to 5 arguments"},
+	}
+
+	for _, d := range tests {
+		if got := d.fn.arity(); got != d.expected {
+			t.Errorf("Expected %s, got %s", d.expected, got)
+		}
+	}
+}
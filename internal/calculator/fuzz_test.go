@@ -0,0 +1,139 @@
+package calculator
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fuzzLocales - locales exercised by the fuzz targets, picked by a fuzzed index
+var fuzzLocales = []string{"C", "en", "de", "de-CH", "fr", "ru"}
+
+// fuzzLocale returns the locale for a fuzzed index.
+func fuzzLocale(i uint8) Locale {
+	l, _ := LookupLocale(fuzzLocales[int(i)%len(fuzzLocales)])
+	return l
+}
+
+func FuzzParseOperand(f *testing.F) {
+	for _, s := range []string{"2\n", "-2.5\n", "abc\n3\n", "1e3\n", "\n", "", "1,5\n", "Inf\n"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		v, err := ParseOperand("", bufio.NewReader(strings.NewReader(input)), io.Discard)
+		if err != nil {
+			return
+		}
+		// the result comes from the first line which is a number
+		for _, line := range strings.SplitAfter(input, "\n") {
+			if w, err := CLocale.ParseNumber(line); err == nil {
+				if w != v && !(math.IsNaN(w) && math.IsNaN(v)) {
+					t.Errorf("Expected %v, got %v for %q", w, v, input)
+				}
+				return
+			}
+		}
+		t.Errorf("Expected an error for %q, got %v", input, v)
+	})
+}
+
+func FuzzParseOperator(f *testing.F) {
+	for _, s := range []string{"+\n", " * \n", "x\n/\n", "++\n", "\n", "", "÷\n"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		op, err := ParseOperator("", bufio.NewReader(strings.NewReader(input)), io.Discard)
+		if err != nil {
+			return
+		}
+		if !isOperator(op) {
+			t.Fatalf("Expected an operator, got %q for %q", op, input)
+		}
+		for _, line := range strings.SplitAfter(input, "\n") {
+			if strings.TrimSpace(line) == string(op) {
+				return
+			}
+		}
+		t.Errorf("Expected %q to be a line of %q", op, input)
+	})
+}
+
+func FuzzParseNumber(f *testing.F) {
+	for _, s := range []string{"1", "-1.5", "1,234.5", "1.234,5", "1'234", "1 234,5", "1e-3", "12,34", ""} {
+		f.Add(s, uint8(0))
+		f.Add(s, uint8(1))
+		f.Add(s, uint8(2))
+	}
+	f.Fuzz(func(t *testing.T, s string, locale uint8) {
+		l := fuzzLocale(locale)
+		v, err := l.ParseNumber(s)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+		// what was parsed is printed and parsed back without loss
+		formatted := l.FormatNumber(v, -1)
+		got, err := l.ParseNumber(formatted)
+		if errors.Is(err, ErrAmbiguousNumber) {
+			got, err = l.ParseNumber(l.FormatNumber(v, 1))
+		}
+		if err != nil {
+			t.Fatalf("%s: %q parsed to %v, %q does not parse: %v", l.Name, s, v, formatted, err)
+		}
+		if got != v {
+			t.Errorf("%s: Expected %v, got %v from %q", l.Name, v, got, formatted)
+		}
+	})
+}
+
+func FuzzParseExpr(f *testing.F) {
+	for _, s := range []string{
+		"2 * (3 + 4)", "-x + 5%", "pmt(5%/12, 360, 200000)", "npv(10%; -100; 60; 60)",
+		"[1, 2] / [0, 1]", "1 / 0", "((1)", "fv(", "1,5 * 2", "irr(-100, 50, 60)",
+	} {
+		f.Add(s, uint8(0), false)
+		f.Add(s, uint8(2), true)
+	}
+	f.Fuzz(func(t *testing.T, s string, locale uint8, interval bool) {
+		e, err := ParseExpr(s, fuzzLocale(locale))
+		if err != nil {
+			return
+		}
+		vars := make(Vars)
+		for _, name := range e.Vars() {
+			vars[name] = 1
+		}
+		o := Options{Locale: fuzzLocale(locale), Precision: DefaultPrecision, Interval: interval}
+		v, err := e.EvalInterval(vars, o)
+		if err != nil {
+			return
+		}
+		if v.Lo > v.Hi {
+			t.Errorf("Expected an ordered interval, got %v for %q", v, s)
+		}
+		if !interval && v.IsPoint() {
+			// a number in interval mode must stay within the bounds
+			o.Interval = true
+			if iv, err := e.EvalInterval(vars, o); err == nil && !math.IsNaN(v.Lo) && !iv.Contains(v.Lo) {
+				t.Errorf("Expected %v to enclose %v for %q", iv, v.Lo, s)
+			}
+		}
+	})
+}
+
+func FuzzRPN(f *testing.F) {
+	for _, s := range []string{"3 4 + 2 *", "1 dup swap roll drop clear", "5% 12 / 360 200000 pmt", "fv:4", "+ + +"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		c := RPN{Options: DefaultOptions()}
+		c.Exec("1 2 3")
+		before := append([]Interval(nil), c.Stack...)
+		if err := c.Exec(line); err != nil && !slices.Equal(c.Stack, before) {
+			t.Errorf("Expected the stack %v to be kept, got %v", before, c.Stack)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrEmptyInterval is returned for intervals whose lower bound exceeds the upper one.
+var ErrEmptyInterval = errors.New("empty interval")
+
+// Interval is a closed range of real numbers [Lo, Hi] which encloses the exact
+// value of a computation. A number x is the degenerate interval [x, x].
+//
+// Every operation rounds its bounds outward, so the result encloses the exact
+// result of the same operation on any numbers taken from the operands.
+type Interval struct {
+	Lo, Hi float64
+}
+
+// Point returns the degenerate interval [x, x].
+func Point(x float64) Interval {
+	return Interval{x, x}
+}
+
+// NewInterval returns the interval [lo, hi] or ErrEmptyInterval if lo > hi.
+func NewInterval(lo, hi float64) (Interval, error) {
+	if lo > hi || math.IsNaN(lo) || math.IsNaN(hi) {
+		return Interval{}, fmt.Errorf("%w: [%g, %g]", ErrEmptyInterval, lo, hi)
+	}
+	return Interval{lo, hi}, nil
+}
+
+// IsPoint reports whether the interval holds a single number.
+func (a Interval) IsPoint() bool {
+	return a.Lo == a.Hi
+}
+
+// Contains reports whether x lies within the interval.
+func (a Interval) Contains(x float64) bool {
+	return a.Lo <= x && x <= a.Hi
+}
+
+// Width returns the length of the interval.
+func (a Interval) Width() float64 {
+	return a.Hi - a.Lo
+}
+
+// Neg returns -a.
+func (a Interval) Neg() Interval {
+	return Interval{-a.Hi, -a.Lo}
+}
+
+// Add returns a + b.
+func (a Interval) Add(b Interval) Interval {
+	lo, _ := sumBounds(a.Lo, b.Lo)
+	_, hi := sumBounds(a.Hi, b.Hi)
+	return Interval{lo, hi}
+}
+
+// Sub returns a - b.
+func (a Interval) Sub(b Interval) Interval {
+	return a.Add(b.Neg())
+}
+
+// Mul returns a * b.
+func (a Interval) Mul(b Interval) Interval {
+	result := Interval{math.Inf(1), math.Inf(-1)}
+	for _, x := range [2]float64{a.Lo, a.Hi} {
+		for _, y := range [2]float64{b.Lo, b.Hi} {
+			lo, hi := prodBounds(x, y)
+			result.Lo = math.Min(result.Lo, lo)
+			result.Hi = math.Max(result.Hi, hi)
+		}
+	}
+	return result
+}
+
+// Div returns a / b.
+//
+// Division by an interval containing zero follows extended interval arithmetic:
+// the result is unbounded on the side where the divisor approaches zero, and the
+// whole real line when a contains zero too or b has zero strictly inside (the
+// smallest interval enclosing the two half-lines). Division by [0, 0] is an error.
+func (a Interval) Div(b Interval) (Interval, error) {
+	if b.Lo == 0 && b.Hi == 0 {
+		return Interval{}, ErrDivisionByZero
+	}
+	if !b.Contains(0) {
+		result := Interval{math.Inf(1), math.Inf(-1)}
+		for _, x := range [2]float64{a.Lo, a.Hi} {
+			for _, y := range [2]float64{b.Lo, b.Hi} {
+				lo, hi := quoBounds(x, y)
+				result.Lo = math.Min(result.Lo, lo)
+				result.Hi = math.Max(result.Hi, hi)
+			}
+		}
+		return result, nil
+	}
+	whole := Interval{math.Inf(-1), math.Inf(1)}
+	switch {
+	case a.Contains(0) || b.Lo < 0 && b.Hi > 0:
+		return whole, nil
+	case a.Hi < 0 && b.Lo == 0:
+		_, hi := quoBounds(a.Hi, b.Hi)
+		return Interval{math.Inf(-1), hi}, nil
+	case a.Hi < 0:
+		lo, _ := quoBounds(a.Hi, b.Lo)
+		return Interval{lo, math.Inf(1)}, nil
+	case b.Lo == 0:
+		lo, _ := quoBounds(a.Lo, b.Hi)
+		return Interval{lo, math.Inf(1)}, nil
+	default:
+		_, hi := quoBounds(a.Lo, b.Lo)
+		return Interval{math.Inf(-1), hi}, nil
+	}
+}
+
+// Round rounds the bounds outward to prec fractional digits,
+// negative prec leaves the interval as is. A bound lying within floating point
+// noise of a multiple of 10^-prec is snapped to it only if that moves it outward,
+// so with one digit [0.30000000000000004, 0.5] becomes [0.3, 0.5] but
+// [0.1, 0.30000000000000004] becomes [0.1, 0.4]: the result always encloses the interval.
+func (a Interval) Round(prec int) Interval {
+	if prec < 0 {
+		return a
+	}
+	p := math.Pow(10, float64(prec))
+	return Interval{roundBound(a.Lo, p, math.Floor), roundBound(a.Hi, p, math.Ceil)}
+}
+
+// roundBound rounds x to a multiple of 1/p in the direction of dir
+// (math.Floor or math.Ceil) unless x is close enough to the nearest one
+// and it lies in that direction too.
+func roundBound(x, p float64, dir func(float64) float64) float64 {
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		return x
+	}
+	scaled := x * p
+	nearest := math.Round(scaled)
+	// dir(scaled) - scaled tells the outward side, snapping to the other one would narrow the interval
+	outward := nearest == scaled || (nearest < scaled) == (dir(scaled) < scaled)
+	if outward && math.Abs(scaled-nearest) <= snapTolerance*math.Max(1, math.Abs(scaled)) {
+		return nearest / p
+	}
+	return dir(scaled) / p
+}
+
+// snapTolerance is the relative distance to a rounding step treated as floating point noise.
+const snapTolerance = 1e-9
+
+// bounds returns floating point numbers enclosing x + e, where x is a rounded
+// result and e the exact rounding error, which is zero for exact results.
+func bounds(x, e float64) (float64, float64) {
+	switch {
+	case math.IsInf(x, 0) || math.IsNaN(x) || e == 0:
+		return x, x
+	case e > 0:
+		return x, math.Nextafter(x, math.Inf(1))
+	default:
+		return math.Nextafter(x, math.Inf(-1)), x
+	}
+}
+
+// sumBounds returns floating point numbers enclosing the exact a + b.
+func sumBounds(a, b float64) (float64, float64) {
+	s := a + b
+	bb := s - a
+	return bounds(s, (a-(s-bb))+(b-bb))
+}
+
+// prodBounds returns floating point numbers enclosing the exact a * b.
+// Zero times infinity is zero, as usual in interval arithmetic.
+func prodBounds(a, b float64) (float64, float64) {
+	if a == 0 || b == 0 {
+		return 0, 0
+	}
+	p := a * b
+	return bounds(p, math.FMA(a, b, -p))
+}
+
+// quoBounds returns floating point numbers enclosing the exact a / b, b != 0.
+func quoBounds(a, b float64) (float64, float64) {
+	if math.IsInf(b, 0) && math.IsInf(a, 0) {
+		return math.Inf(-1), math.Inf(1)
+	}
+	q := a / b
+	if math.IsInf(b, 0) || math.IsInf(a, 0) {
+		return q, q
+	}
+	// the remainder a - q*b is exact and has the sign of the error times b
+	r := -math.FMA(q, b, -a)
+	if b < 0 {
+		r = -r
+	}
+	return bounds(q, r)
+}
@@ -0,0 +1,185 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// ExampleEvaluateInterval
+func ExampleEvaluateInterval() {
+	o := DefaultOptions()
+	result, _ := EvaluateInterval("[1.9, 2.1] * [2.9, 3.1]", o)
+	fmt.Println(o.FormatInterval(result))
+	// Output: [5.51, 6.51]
+}
+
+func TestInterval_Arithmetic(t *testing.T) {
+	a := Interval{1, 2}
+	b := Interval{-3, 4}
+	tests := []struct {
+		name     string
+		got      Interval
+		expected Interval
+	}{
+		{"add", a.Add(b), Interval{-2, 6}},
+		{"sub", a.Sub(b), Interval{-3, 5}},
+		{"mul", a.Mul(b), Interval{-6, 8}},
+		{"neg", b.Neg(), Interval{-4, 3}},
+		{"mul by infinite", Interval{0, 1}.Mul(Interval{1, math.Inf(1)}), Interval{0, math.Inf(1)}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if d.got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, d.got)
+			}
+		})
+	}
+}
+
+func TestInterval_Div(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name     string
+		a, b     Interval
+		expected Interval
+		err      error
+	}{
+		{"positive", Interval{1, 2}, Interval{4, 8}, Interval{0.125, 0.5}, nil},
+		{"negative divisor", Interval{1, 2}, Interval{-2, -1}, Interval{-2, -0.5}, nil},
+		{"zero", Interval{1, 2}, Interval{0, 0}, Interval{}, ErrDivisionByZero},
+		{"positive by [0, b]", Interval{1, 2}, Interval{0, 4}, Interval{0.25, inf}, nil},
+		{"positive by [a, 0]", Interval{1, 2}, Interval{-4, 0}, Interval{-inf, -0.25}, nil},
+		{"negative by [0, b]", Interval{-2, -1}, Interval{0, 4}, Interval{-inf, -0.25}, nil},
+		{"negative by [a, 0]", Interval{-2, -1}, Interval{-4, 0}, Interval{0.25, inf}, nil},
+		{"zero inside divisor", Interval{1, 2}, Interval{-1, 1}, Interval{-inf, inf}, nil},
+		{"zero in both", Interval{-1, 2}, Interval{0, 1}, Interval{-inf, inf}, nil},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := d.a.Div(d.b)
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestInterval_OutwardRounding(t *testing.T) {
+	// 0.1 + 0.2 is inexact in floating point, the bounds must enclose the float sum
+	sum := Point(0.1).Add(Point(0.2))
+	if sum.IsPoint() || !sum.Contains(0.1+0.2) {
+		t.Errorf("Expected a proper enclosure of 0.1+0.2, got %v", sum)
+	}
+	if got := Point(0.5).Add(Point(0.25)); !got.IsPoint() {
+		t.Errorf("Expected exact sum to stay a point, got %v", got)
+	}
+	third, _ := Point(1).Div(Point(3))
+	if third.IsPoint() || third.Width() > 1e-16 {
+		t.Errorf("Expected a one ulp enclosure of 1/3, got %v", third)
+	}
+	lit, _ := literalValue("0.1", false)
+	if !lit.isPoint() || lit.iv.IsPoint() || !lit.iv.Contains(0.1) {
+		t.Errorf("Expected the exact 0.1 in a one ulp enclosure, got %v", lit.iv)
+	}
+}
+
+func TestInterval_Round(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Interval
+		prec     int
+		expected Interval
+	}{
+		{"outward", Interval{0.3331, 0.3339}, 3, Interval{0.333, 0.334}},
+		{"negative", Interval{-1.25, -1.15}, 1, Interval{-1.3, -1.1}},
+		{"noise snapped outward", Interval{0.30000000000000004, 0.29999999999999993}, 3, Point(0.3)},
+		{"noise not snapped inward", Interval{0.29999999999999993, 0.30000000000000004}, 3, Interval{0.299, 0.301}},
+		{"lower bound below a step", Interval{0.29999999999, 0.5}, 1, Interval{0.2, 0.5}},
+		{"full precision", Interval{0.1, 0.2}, -1, Interval{0.1, 0.2}},
+		{"infinite", Interval{math.Inf(-1), 1.5}, 0, Interval{math.Inf(-1), 2}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := d.input.Round(d.prec); got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		interval bool
+		prec     int
+		input    string
+		expected string
+		err      error
+	}{
+		{"product", "C", false, 3, "[1.9, 2.1] * [2.9, 3.1]", "[5.51, 6.51]", nil},
+		{"bounds are expressions", "C", false, 3, "[-1, 1+1] + 1", "[0, 3]", nil},
+		{"division through zero", "C", false, 3, "1 / [0, 2]", "[0.5, +Inf]", nil},
+		{"division by zero interval", "C", false, 3, "1 / [0, 0]", "", ErrDivisionByZero},
+		{"empty", "C", false, 3, "[2, 1]", "", ErrEmptyInterval},
+		{"unclosed", "C", false, 3, "[1, 2", "", ErrSyntax},
+		{"locale separator", "de", false, 1, "[1,5; 2,5] * 2", "[3; 5]", nil},
+		{"numbers stay numbers", "C", false, 3, "1 / 3 * 3", "1", nil},
+		{"rounding error shown", "C", true, 3, "1 / 3 * 3", "[0.999, 1.002]", nil},
+		{"exact in interval mode", "C", true, 3, "0.1 + 0.2", "0.3", nil},
+		{"function of interval", "C", false, 2, "pmt(5%/12, 360, [190000, 210000])", "[-1127.33, -1019.96]", nil},
+		{"monotonic in every argument", "C", false, 2, "compound([900, 1000], [4%, 5%], [9, 10])", "[1280.98, 1628.9]", nil},
+		{"non-monotonic rate", "C", false, 2, "npv([5%, 15%], -100, 60, 60)", "", ErrDomain},
+		{"irr of intervals", "C", false, 2, "irr([-110, -100], 60, 60)", "", ErrDomain},
+		{"npv of interval flows", "C", false, 2, "npv(10%, -100, [50, 60], 60)", "[-4.51, 3.76]", nil},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, _ := LookupLocale(d.locale)
+			o := Options{Locale: l, Precision: d.prec, Interval: d.interval}
+			got, err := EvaluateInterval(d.input, o)
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s := o.FormatInterval(got); s != d.expected {
+				t.Errorf("Expected %s, got %s", d.expected, s)
+			}
+		})
+	}
+}
+
+func TestExpr_EvalVarsInterval(t *testing.T) {
+	_, err := Evaluate("[1, 2]", DefaultOptions())
+	if !errors.Is(err, ErrNotPoint) {
+		t.Errorf("Expected ErrNotPoint, got %v", err)
+	}
+}
+
+func TestOptions_OperateInterval(t *testing.T) {
+	o := Options{Locale: CLocale, Precision: 2}
+	got, err := o.OperateInterval(Point(2), '/', Point(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != (Interval{0.66, 0.67}) {
+		t.Errorf("Expected [0.66, 0.67], got %v", got)
+	}
+}
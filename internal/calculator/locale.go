@@ -0,0 +1,201 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Locale describes how numbers are written in a particular language or region:
+// which rune separates the fractional part and which runes may group
+// the digits of the integer part.
+type Locale struct {
+	Name    string // canonical locale name, e.g. "de"
+	Decimal rune   // decimal separator
+	Group   []rune // accepted grouping separators, the first one is used for output
+}
+
+// Errors returned by Locale.ParseNumber.
+var (
+	ErrInvalidNumber   = errors.New("invalid number")   // Returned for malformed numbers.
+	ErrAmbiguousNumber = errors.New("ambiguous number") // Returned when a number reads differently in other locales.
+	ErrUnknownLocale   = errors.New("unknown locale")   // Returned by LookupLocale for unsupported names.
+)
+
+// LocaleEnv is the environment variable consulted by LocaleFromEnv before LC_ALL, LC_NUMERIC and LANG.
+const LocaleEnv = "CALC_LOCALE"
+
+// CLocale is the default locale: a dot as decimal separator and no grouping,
+// exactly what strconv.ParseFloat accepts.
+var CLocale = Locale{Name: "C", Decimal: '.'}
+
+// locales - all of supported locales, keyed by lowercase name
+var locales = map[string]Locale{
+	"c":     CLocale,
+	"posix": CLocale,
+	"en":    {Name: "en", Decimal: '.', Group: []rune{','}},
+	"de":    {Name: "de", Decimal: ',', Group: []rune{'.'}},
+	"de-ch": {Name: "de-CH", Decimal: '.', Group: []rune{'\'', '\u2019'}},
+	"fr":    {Name: "fr", Decimal: ',', Group: []rune{' ', '\u00a0', '\u202f'}},
+	"ru":    {Name: "ru", Decimal: ',', Group: []rune{' ', '\u00a0', '\u202f'}},
+}
+
+// LookupLocale returns the locale with the given name.
+// Names are case-insensitive and may be given in POSIX form ("de_DE.UTF-8"):
+// the full name is tried first and then the bare language.
+func LookupLocale(name string) (Locale, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(n, ".@"); i >= 0 {
+		n = n[:i]
+	}
+	n = strings.ReplaceAll(n, "_", "-")
+	if l, ok := locales[n]; ok {
+		return l, nil
+	}
+	if i := strings.IndexByte(n, '-'); i >= 0 {
+		if l, ok := locales[n[:i]]; ok {
+			return l, nil
+		}
+	}
+	return Locale{}, fmt.Errorf("%w: %q", ErrUnknownLocale, name)
+}
+
+// LocaleFromEnv returns the locale named by the first of CALC_LOCALE, LC_ALL,
+// LC_NUMERIC and LANG which is set to a supported locale, CLocale if none is.
+func LocaleFromEnv() Locale {
+	for _, env := range []string{LocaleEnv, "LC_ALL", "LC_NUMERIC", "LANG"} {
+		if l, err := LookupLocale(os.Getenv(env)); err == nil {
+			return l
+		}
+	}
+	return CLocale
+}
+
+// isGroup reports whether r is one of the grouping separators of the locale.
+func (l Locale) isGroup(r rune) bool {
+	for _, g := range l.Group {
+		if r == g {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNumber converts a number written in the locale to float64.
+//
+// Grouping separators are accepted in the integer part only and must split it
+// into groups of three digits. A number with a single '.' or ',' grouping
+// separator and no decimal part (like "1,234" in "en") is rejected with
+// ErrAmbiguousNumber because other locales read that separator as a decimal one.
+func (l Locale) ParseNumber(s string) (float64, error) {
+	c, err := l.canonical(s)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(c, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, strings.TrimSpace(s))
+	}
+	return v, nil
+}
+
+// canonical rewrites a number written in the locale in strconv syntax
+// without grouping, see ParseNumber for the rules.
+func (l Locale) canonical(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(l.Group) == 0 && l.Decimal == '.' {
+		return s, nil
+	}
+	invalid := fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	var b strings.Builder
+	rs := []rune(s)
+	i := 0
+	if i < len(rs) && (rs[i] == '+' || rs[i] == '-') {
+		b.WriteRune(rs[i])
+		i++
+	}
+	var (
+		groups     []int // digit counts of the integer part groups
+		digits     int
+		seps       []rune
+		hasDecimal bool
+		fracDigits int
+	)
+loop:
+	for ; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			if hasDecimal {
+				fracDigits++
+			} else {
+				digits++
+			}
+		case r == l.Decimal && !hasDecimal:
+			hasDecimal = true
+			b.WriteByte('.')
+		case l.isGroup(r) && !hasDecimal:
+			groups = append(groups, digits)
+			seps = append(seps, r)
+			digits = 0
+		default:
+			break loop
+		}
+	}
+	if i < len(rs) {
+		if (rs[i] != 'e' && rs[i] != 'E') || digits+fracDigits == 0 {
+			return "", invalid
+		}
+		b.WriteString(string(rs[i:]))
+	}
+	groups = append(groups, digits)
+	if digits+fracDigits == 0 && len(groups) == 1 {
+		return "", invalid
+	}
+	if len(groups) > 1 {
+		if groups[0] < 1 || groups[0] > 3 {
+			return "", invalid
+		}
+		for _, g := range groups[1:] {
+			if g != 3 {
+				return "", invalid
+			}
+		}
+		if len(seps) == 1 && !hasDecimal && (seps[0] == '.' || seps[0] == ',') {
+			return "", fmt.Errorf("%w: %q", ErrAmbiguousNumber, s)
+		}
+	}
+	return b.String(), nil
+}
+
+// FormatNumber formats v with prec fractional digits (-1 means the smallest
+// number of digits necessary to represent v exactly) using the decimal and
+// the first grouping separator of the locale.
+func (l Locale) FormatNumber(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.ContainsFunc(s, unicode.IsLetter) {
+		return s // Inf and NaN
+	}
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range intPart {
+		if i > 0 && len(l.Group) > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteRune(l.Group[0])
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		b.WriteRune(l.Decimal)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
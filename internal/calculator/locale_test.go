@@ -0,0 +1,188 @@
+package calculator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ExampleLocale_FormatNumber
+func ExampleLocale_FormatNumber() {
+	l, _ := LookupLocale("de_DE.UTF-8")
+	fmt.Println(l.FormatNumber(1234567.891, 2))
+	// Output: 1.234.567,89
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		expErr   bool
+	}{
+		{"plain", "de", "de", false},
+		{"posix form", "ru_RU.UTF-8", "ru", false},
+		{"region", "de_CH", "de-CH", false},
+		{"upper case", "FR", "fr", false},
+		{"C", "C", "C", false},
+		{"unknown", "xx", "", true},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := LookupLocale(d.input)
+			if d.expErr {
+				if !errors.Is(err, ErrUnknownLocale) {
+					t.Fatalf("Expected ErrUnknownLocale, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Name != d.expected {
+				t.Errorf("Expected %s, got %s", d.expected, got.Name)
+			}
+		})
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"nothing set", map[string]string{}, "C"},
+		{"LANG", map[string]string{"LANG": "ru_RU.UTF-8"}, "ru"},
+		{"LC_NUMERIC over LANG", map[string]string{"LC_NUMERIC": "de_DE.UTF-8", "LANG": "ru_RU.UTF-8"}, "de"},
+		{"LC_ALL over LC_NUMERIC", map[string]string{"LC_ALL": "fr_FR", "LC_NUMERIC": "de_DE.UTF-8"}, "fr"},
+		{"CALC_LOCALE first", map[string]string{LocaleEnv: "ru", "LC_ALL": "fr_FR"}, "ru"},
+		{"unsupported skipped", map[string]string{LocaleEnv: "klingon", "LC_ALL": "tlh", "LC_NUMERIC": "de_DE.UTF-8"}, "de"},
+		{"all unsupported", map[string]string{LocaleEnv: "klingon", "LANG": "tlh"}, "C"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			for _, env := range []string{LocaleEnv, "LC_ALL", "LC_NUMERIC", "LANG"} {
+				t.Setenv(env, d.env[env])
+			}
+			if got := LocaleFromEnv(); got.Name != d.expected {
+				t.Errorf("Expected %s, got %s", d.expected, got.Name)
+			}
+		})
+	}
+}
+
+func TestLocale_ParseNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		input    string
+		expected float64
+		err      error
+	}{
+		{"C plain", "C", "3.14", 3.14, nil},
+		{"C comma", "C", "3,14", 0, ErrInvalidNumber},
+		{"en grouping", "en", "1,234,567.5", 1234567.5, nil},
+		{"en ambiguous", "en", "1,234", 0, ErrAmbiguousNumber},
+		{"en bad group", "en", "12,34", 0, ErrInvalidNumber},
+		{"en negative", "en", "-1,000.25", -1000.25, nil},
+		{"de decimal comma", "de", "3,14", 3.14, nil},
+		{"de grouping", "de", "1.000.000,5", 1000000.5, nil},
+		{"de ambiguous", "de", "1.234", 0, ErrAmbiguousNumber},
+		{"de dot decimal", "de", "3.14", 0, ErrInvalidNumber},
+		{"de exponent", "de", "1,5e3", 1500, nil},
+		{"fr space", "fr", "1 000,5", 1000.5, nil},
+		{"fr nbsp", "fr", "1\u00a0000,5", 1000.5, nil},
+		{"ru narrow nbsp", "ru", "12\u202f345", 12345, nil},
+		{"ru group after decimal", "ru", "1,000 5", 0, ErrInvalidNumber},
+		{"de-CH apostrophe", "de-CH", "1'234.5", 1234.5, nil},
+		{"de-CH single apostrophe", "de-CH", "1'234", 1234, nil},
+		{"empty", "de", "", 0, ErrInvalidNumber},
+		{"letters", "de", "45f", 0, ErrInvalidNumber},
+		{"leading group", "en", ",123", 0, ErrInvalidNumber},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, err := LookupLocale(d.locale)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := l.ParseNumber(d.input)
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %f, got %f", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestLocale_FormatNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		input    float64
+		prec     int
+		expected string
+	}{
+		{"C shortest", "C", 1234.5, -1, "1234.5"},
+		{"en", "en", 1234567.5, 2, "1,234,567.50"},
+		{"en small", "en", 123, 0, "123"},
+		{"de negative", "de", -1234.5, 1, "-1.234,5"},
+		{"fr", "fr", 1000.5, -1, "1 000,5"},
+		{"de-CH", "de-CH", 1234567, 0, "1'234'567"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, _ := LookupLocale(d.locale)
+			if got := l.FormatNumber(d.input, d.prec); got != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseLocaleOperand(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    float64
+		errContains string
+	}{
+		{"decimal comma", "3,5\n", 3.5, ""},
+		{"invalid then valid", "3.5\n3,5\n", 3.5, "Invalid input"},
+		{"ambiguous then valid", "1.234\n1.234,0\n", 1234, "Ambiguous input"},
+	}
+
+	de, _ := LookupLocale("de")
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(d.input))
+			var output bytes.Buffer
+			got, err := ParseLocaleOperand("Enter operand: ", de, reader, &output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %f, got %f", d.expected, got)
+			}
+			if !strings.Contains(output.String(), d.errContains) {
+				t.Errorf("Expected %q in output, got: %s", d.errContains, output.String())
+			}
+		})
+	}
+}
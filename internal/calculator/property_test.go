@@ -0,0 +1,153 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// finite maps an arbitrary float64 to a finite one of a moderate magnitude.
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return math.Mod(v, 1e12)
+}
+
+func TestProperty_Commutativity(t *testing.T) {
+	o := Options{Locale: CLocale, Precision: -1}
+	for _, op := range []rune{'+', '*'} {
+		t.Run(string(op), func(t *testing.T) {
+			commutes := func(a, b float64) bool {
+				a, b = finite(a), finite(b)
+				left, _ := o.Operate(a, op, b)
+				right, _ := o.Operate(b, op, a)
+				return left == right
+			}
+			if err := quick.Check(commutes, nil); err != nil {
+				t.Error(err)
+			}
+			intervals := func(a, b, c, d float64) bool {
+				x, y := Interval{math.Min(a, b), math.Max(a, b)}, Interval{math.Min(c, d), math.Max(c, d)}
+				left, _ := o.OperateInterval(x, op, y)
+				right, _ := o.OperateInterval(y, op, x)
+				return left == right || math.IsNaN(left.Lo) && math.IsNaN(right.Lo)
+			}
+			if err := quick.Check(intervals, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestProperty_FormatParseRoundTrip(t *testing.T) {
+	for _, name := range fuzzLocales {
+		t.Run(name, func(t *testing.T) {
+			l, _ := LookupLocale(name)
+			roundTrip := func(v float64, prec uint8) bool {
+				v = finite(v)
+				p := int(prec%8) - 1 // full precision or up to 6 digits
+				want, _ := CLocale.ParseNumber(CLocale.FormatNumber(v, p))
+				got, err := l.ParseNumber(l.FormatNumber(v, p))
+				if errors.Is(err, ErrAmbiguousNumber) {
+					// whole numbers with one '.' or ',' group are rejected by design
+					return p <= 0 && math.Abs(want) >= 1000 && math.Abs(want) < 1e6
+				}
+				return err == nil && got == want
+			}
+			if err := quick.Check(roundTrip, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// ratExpr is a random expression with its exact value
+// computed independently of the evaluator with big.Rat.
+type ratExpr struct {
+	s     string
+	v     *big.Rat
+	scale float64 // the largest magnitude of a subexpression, bounds the rounding error
+	prec  int     // precedence of the top operator, 3 for literals and parentheses
+}
+
+// operand returns the expression as an operand of an operator with precedence prec,
+// in parentheses unless it binds tighter: the right operand of - and / is
+// parenthesized on equal precedence too.
+func (e ratExpr) operand(prec int, right bool) string {
+	if e.prec > prec || e.prec == prec && !right {
+		return e.s
+	}
+	return "(" + e.s + ")"
+}
+
+// genExpr generates a random expression of + - * / with parentheses where needed,
+// unary minus and literals with up to two fractional digits.
+// Divisors are kept away from zero so the float result stays close to the exact one.
+func genExpr(r *rand.Rand, depth int) ratExpr {
+	if depth == 0 || r.Intn(4) == 0 {
+		n := r.Int63n(10000)
+		v := big.NewRat(n, 100)
+		s := v.FloatString(2)
+		if r.Intn(2) == 0 {
+			s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+		}
+		f, _ := v.Float64()
+		return ratExpr{s, v, f, 3}
+	}
+	if r.Intn(8) == 0 {
+		e := genExpr(r, depth-1)
+		return ratExpr{"-" + e.operand(3, true), new(big.Rat).Neg(e.v), e.scale, 3}
+	}
+	left, right := genExpr(r, depth-1), genExpr(r, depth-1)
+	op := "+-*/"[r.Intn(4)]
+	v := new(big.Rat)
+	switch op {
+	case '+':
+		v.Add(left.v, right.v)
+	case '-':
+		v.Sub(left.v, right.v)
+	case '*':
+		v.Mul(left.v, right.v)
+	case '/':
+		if f, _ := right.v.Float64(); math.Abs(f) < 0.01 {
+			return left
+		}
+		v.Quo(left.v, right.v)
+	}
+	f, _ := v.Float64()
+	scale := math.Max(math.Abs(f), math.Max(left.scale, right.scale))
+	prec := operators[rune(op)].prec
+	s := fmt.Sprintf("%s %c %s", left.operand(prec, false), op, right.operand(prec, op == '-' || op == '/'))
+	return ratExpr{s, v, scale, prec}
+}
+
+func TestDifferential_Evaluate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := Options{Locale: CLocale, Precision: -1}
+	for range 2000 {
+		e := genExpr(r, 4)
+		got, err := Evaluate(e.s, o)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", e.s, err)
+		}
+		want, _ := e.v.Float64()
+		if math.Abs(got-want) > 1e-9*math.Max(1, e.scale) {
+			t.Fatalf("%s: Expected %v, got %v", e.s, want, got)
+		}
+		// the exact value lies within the bounds of interval mode
+		iv, err := EvaluateInterval(e.s, Options{Locale: CLocale, Precision: -1, Interval: true})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", e.s, err)
+		}
+		lo, hi := new(big.Rat).SetFloat64(iv.Lo), new(big.Rat).SetFloat64(iv.Hi)
+		if lo.Cmp(e.v) > 0 || hi.Cmp(e.v) < 0 {
+			t.Fatalf("%s: Expected %v to enclose %s", e.s, iv, e.v.FloatString(20))
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrStackUnderflow is returned when the stack holds fewer values than a word needs.
+var ErrStackUnderflow = errors.New("too few values on the stack")
+
+// RPN is a Reverse Polish Notation calculator. It shares operators and
+// functions with expressions and keeps full precision on the stack,
+// values are rounded to the precision of the options only for display.
+type RPN struct {
+	Stack   []Interval // the top of the stack is the last element
+	Options Options
+
+	values []value // exact values behind Stack as of the last Exec
+}
+
+// stack returns the values of Stack, exact where Stack still holds
+// the results of the last Exec.
+func (c *RPN) stack() []value {
+	stack := make([]value, len(c.Stack))
+	for i, iv := range c.Stack {
+		stack[i] = floatValue(iv)
+		if i < len(c.values) && c.values[i].interval() == iv {
+			stack[i] = c.values[i]
+		}
+	}
+	return stack
+}
+
+// stackCommands - all of stack manipulation words
+var stackCommands = map[string]func(stack []value) ([]value, error){
+	"dup": func(stack []value) ([]value, error) {
+		if len(stack) < 1 {
+			return nil, ErrStackUnderflow
+		}
+		return append(stack, stack[len(stack)-1]), nil
+	},
+	"swap": func(stack []value) ([]value, error) {
+		if len(stack) < 2 {
+			return nil, ErrStackUnderflow
+		}
+		n := len(stack)
+		stack[n-1], stack[n-2] = stack[n-2], stack[n-1]
+		return stack, nil
+	},
+	"drop": func(stack []value) ([]value, error) {
+		if len(stack) < 1 {
+			return nil, ErrStackUnderflow
+		}
+		return stack[:len(stack)-1], nil
+	},
+	"roll": func(stack []value) ([]value, error) {
+		if len(stack) < 1 {
+			return nil, ErrStackUnderflow
+		}
+		top := stack[len(stack)-1]
+		copy(stack[1:], stack[:len(stack)-1])
+		stack[0] = top
+		return stack, nil
+	},
+	"clear": func([]value) ([]value, error) {
+		return nil, nil
+	},
+}
+
+// Exec executes a line of words separated by spaces from left to right:
+//   - numbers written in the locale, "5%" included, are pushed on the stack;
+//   - operators pop two values and push the result;
+//   - functions pop as many values as they take, all of the stack for npv and irr;
+//     "name:N" calls a function with N arguments, e.g. "fv:4";
+//   - dup, swap, drop, roll (the top value goes to the bottom) and clear manipulate the stack.
+//
+// A line which fails leaves the stack as it was before the line.
+func (c *RPN) Exec(line string) error {
+	stack := c.stack()
+	e := c.Options.env(nil)
+	for _, word := range strings.Fields(line) {
+		var err error
+		stack, err = exec(stack, word, c.Options.Locale, e)
+		if err != nil {
+			return fmt.Errorf("%s: %w", word, err)
+		}
+	}
+	c.values = stack
+	c.Stack = make([]Interval, len(stack))
+	for i, v := range stack {
+		c.Stack[i] = v.interval()
+	}
+	return nil
+}
+
+// exec executes a single word.
+func exec(stack []value, word string, l Locale, e env) ([]value, error) {
+	lower := strings.ToLower(word)
+	if cmd, ok := stackCommands[lower]; ok {
+		return cmd(stack)
+	}
+	if r := []rune(word); len(r) == 1 && isOperator(r[0]) {
+		if len(stack) < 2 {
+			return nil, ErrStackUnderflow
+		}
+		n := len(stack)
+		v, err := e.operate(r[0], stack[n-2], stack[n-1])
+		if err != nil {
+			return nil, err
+		}
+		return append(stack[:n-2], v), nil
+	}
+	name, count, hasCount := strings.Cut(lower, ":")
+	if fn, ok := functions[name]; ok {
+		n := fn.minArgs
+		if fn.maxArgs < 0 {
+			n = len(stack)
+		}
+		if hasCount {
+			var err error
+			if n, err = strconv.Atoi(count); err != nil || n < fn.minArgs || fn.maxArgs >= 0 && n > fn.maxArgs {
+				return nil, fmt.Errorf("%w: %s takes %s", ErrArgCount, name, fn.arity())
+			}
+		}
+		if len(stack) < n || n < fn.minArgs {
+			return nil, ErrStackUnderflow
+		}
+		v, err := e.call(name, fn, stack[len(stack)-n:])
+		if err != nil {
+			return nil, err
+		}
+		return append(stack[:len(stack)-n], v), nil
+	}
+	n, err := parseLiteral(word, l)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := n.eval(e)
+	return append(stack, v), nil
+}
+
+// parseLiteral parses a number word of the RPN input like the expression lexer does.
+func parseLiteral(s string, l Locale) (numberNode, error) {
+	s, percent := strings.CutSuffix(strings.TrimSpace(s), "%")
+	c, err := l.canonical(s)
+	if err != nil {
+		return numberNode{}, err
+	}
+	n, err := newNumberNode(c, percent)
+	if err != nil {
+		return numberNode{}, fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	return n, nil
+}
+
+// PrintStack writes the stack with the top value last, every value on its own
+// line numbered by its level like on HP calculators: "2: 3" above "1: 7".
+func (c *RPN) PrintStack(writer io.Writer) {
+	if len(c.Stack) == 0 {
+		fmt.Fprintln(writer, "(empty)")
+		return
+	}
+	for i, v := range c.stack() {
+		fmt.Fprintf(writer, "%d: %s\n", len(c.Stack)-i, c.Options.FormatInterval(c.Options.roundResult(v)))
+	}
+}
@@ -0,0 +1,89 @@
+package calculator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// ExampleRPN
+func ExampleRPN() {
+	c := RPN{Options: DefaultOptions()}
+	c.Exec("3 4 + 2 *")
+	c.Exec("10")
+	c.PrintStack(os.Stdout)
+	// Output:
+	// 2: 14
+	// 1: 10
+}
+
+func TestRPN_Exec(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		interval bool
+		lines    []string
+		expected string
+		err      error
+	}{
+		{"arithmetic", "C", false, []string{"3 4 + 2 *"}, "1: 14\n", nil},
+		{"lines keep the stack", "C", false, []string{"3", "4", "-"}, "1: -1\n", nil},
+		{"negative number", "C", false, []string{"-5 2 /"}, "1: -2.5\n", nil},
+		{"percent", "C", false, []string{"200 5% *"}, "1: 10\n", nil},
+		{"locale", "de", false, []string{"1.000,5 0,5 +"}, "1: 1.001\n", nil},
+		{"dup", "C", false, []string{"3 dup *"}, "1: 9\n", nil},
+		{"swap", "C", false, []string{"1 2 swap -"}, "1: 1\n", nil},
+		{"drop", "C", false, []string{"1 2 drop"}, "1: 1\n", nil},
+		{"roll", "C", false, []string{"1 2 3 roll"}, "3: 3\n2: 1\n1: 2\n", nil},
+		{"clear", "C", false, []string{"1 2 CLEAR"}, "(empty)\n", nil},
+		{"full precision kept", "C", false, []string{"1 3 / 3 *"}, "1: 1\n", nil},
+		{"function", "C", false, []string{"0", "5% 12 / 360 200000 pmt"}, "2: 0\n1: -1073.643\n", nil},
+		{"function with count", "C", false, []string{"10% 1 100 0 fv:4"}, "1: -100\n", nil},
+		{"variadic function", "C", false, []string{"10% -100 60 60 npv"}, "1: 3.757\n", nil},
+		{"interval mode", "C", true, []string{"1 3 / 3 *"}, "1: [0.999, 1.002]\n", nil},
+		{"underflow", "C", false, []string{"1 +"}, "", ErrStackUnderflow},
+		{"function underflow", "C", false, []string{"1 2 pmt"}, "", ErrStackUnderflow},
+		{"wrong count", "C", false, []string{"1 2 3 4 5 6 pmt:6"}, "", ErrArgCount},
+		{"unknown word", "C", false, []string{"1 foo"}, "", ErrInvalidNumber},
+		{"division by zero", "C", false, []string{"1 0 /"}, "", ErrDivisionByZero},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			l, _ := LookupLocale(d.locale)
+			c := RPN{Options: Options{Locale: l, Precision: DefaultPrecision, Interval: d.interval}}
+			var err error
+			for _, line := range d.lines {
+				if err = c.Exec(line); err != nil {
+					break
+				}
+			}
+			if d.err != nil {
+				if !errors.Is(err, d.err) {
+					t.Fatalf("Expected %v, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var out bytes.Buffer
+			c.PrintStack(&out)
+			if out.String() != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRPN_ExecFailureKeepsStack(t *testing.T) {
+	c := RPN{Options: DefaultOptions()}
+	c.Exec("1 2")
+	if err := c.Exec("+ +"); !errors.Is(err, ErrStackUnderflow) {
+		t.Fatalf("Expected %v, got %v", ErrStackUnderflow, err)
+	}
+	if len(c.Stack) != 2 || c.Stack[0] != Point(1) || c.Stack[1] != Point(2) {
+		t.Errorf("Expected [1 2] stack, got %v", c.Stack)
+	}
+}
@@ -0,0 +1,209 @@
+package calculator
+
+import (
+	"math"
+	"math/big"
+)
+
+// value is the result of evaluating an expression: an interval of floating point
+// bounds and, where they are known, its exact rational bounds.
+//
+// Decimal literals like 1.9 have no exact floating point value, so operations
+// on intervals are done on the exact bounds and only rounded when shown:
+// [1.9, 2.1] * [2.9, 3.1] is exactly [5.51, 6.51].
+type value struct {
+	iv     Interval // floating point bounds enclosing the exact ones
+	lo, hi *big.Rat // exact bounds, nil where the floating point bound is exact or infinite
+}
+
+// floatValue returns the value of an interval with exact floating point bounds.
+func floatValue(a Interval) value {
+	return value{iv: a}
+}
+
+// ratValue returns the value of the interval with the exact bounds [lo, hi].
+func ratValue(lo, hi *big.Rat) value {
+	return value{iv: Interval{floatBelow(lo), floatAbove(hi)}, lo: lo, hi: hi}
+}
+
+// literalValue returns the exact value of the decimal number s written in
+// strconv syntax, divided by 100 when percent is set.
+func literalValue(s string, percent bool) (value, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return value{}, ErrInvalidNumber
+	}
+	if percent {
+		r.Quo(r, big.NewRat(100, 1))
+	}
+	return ratValue(r, r), nil
+}
+
+// exactBound returns the exact bound r, or x if r is nil, and nil if that is infinite.
+func exactBound(r *big.Rat, x float64) *big.Rat {
+	if r != nil {
+		return r
+	}
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		return nil
+	}
+	return new(big.Rat).SetFloat64(x)
+}
+
+// bounds returns the exact bounds of v, nil if v is unbounded on that side.
+func (v value) bounds() (*big.Rat, *big.Rat) {
+	return exactBound(v.lo, v.iv.Lo), exactBound(v.hi, v.iv.Hi)
+}
+
+// isPoint reports whether v holds a single number.
+func (v value) isPoint() bool {
+	lo, hi := v.bounds()
+	if lo == nil || hi == nil {
+		return v.iv.IsPoint()
+	}
+	return lo.Cmp(hi) == 0
+}
+
+// number returns the floating point number nearest to the lower bound,
+// the number v holds if it is a point.
+func (v value) number() float64 {
+	if v.lo != nil {
+		f, _ := v.lo.Float64()
+		return f
+	}
+	return v.iv.Lo
+}
+
+// interval returns v as an Interval: a number as the nearest floating point
+// number, the floating point bounds otherwise.
+func (v value) interval() Interval {
+	if v.isPoint() {
+		return Point(v.number())
+	}
+	return v.iv
+}
+
+// neg returns -v.
+func (v value) neg() value {
+	return value{iv: v.iv.Neg(), lo: negRat(v.hi), hi: negRat(v.lo)}
+}
+
+// negRat returns -r, nil if r is nil.
+func negRat(r *big.Rat) *big.Rat {
+	if r == nil {
+		return nil
+	}
+	return new(big.Rat).Neg(r)
+}
+
+// addValues returns a + b, exactly if both are bounded.
+func addValues(a, b value) value {
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	if alo == nil || ahi == nil || blo == nil || bhi == nil {
+		return floatValue(a.iv.Add(b.iv))
+	}
+	return ratValue(new(big.Rat).Add(alo, blo), new(big.Rat).Add(ahi, bhi))
+}
+
+// mulValues returns a * b, exactly if both are bounded.
+func mulValues(a, b value) value {
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	if alo == nil || ahi == nil || blo == nil || bhi == nil {
+		return floatValue(a.iv.Mul(b.iv))
+	}
+	return ratValue(extremes(func(x, y *big.Rat) *big.Rat { return new(big.Rat).Mul(x, y) }, alo, ahi, blo, bhi))
+}
+
+// divValues returns a / b, exactly if both are bounded and b does not contain zero.
+// Division by an interval containing zero follows Interval.Div.
+func divValues(a, b value) (value, error) {
+	alo, ahi := a.bounds()
+	blo, bhi := b.bounds()
+	if alo == nil || ahi == nil || blo == nil || bhi == nil || blo.Sign() <= 0 && bhi.Sign() >= 0 {
+		iv, err := a.iv.Div(b.iv)
+		return floatValue(iv), err
+	}
+	return ratValue(extremes(func(x, y *big.Rat) *big.Rat { return new(big.Rat).Quo(x, y) }, alo, ahi, blo, bhi)), nil
+}
+
+// extremes returns the least and the greatest of op applied to every pair of bounds.
+func extremes(op func(x, y *big.Rat) *big.Rat, alo, ahi, blo, bhi *big.Rat) (*big.Rat, *big.Rat) {
+	var lo, hi *big.Rat
+	for _, x := range [2]*big.Rat{alo, ahi} {
+		for _, y := range [2]*big.Rat{blo, bhi} {
+			r := op(x, y)
+			if lo == nil || r.Cmp(lo) < 0 {
+				lo = r
+			}
+			if hi == nil || r.Cmp(hi) > 0 {
+				hi = r
+			}
+		}
+	}
+	return lo, hi
+}
+
+// round rounds the exact bounds of v outward to prec fractional digits,
+// negative prec leaves v as is. Infinite bounds stay infinite.
+func (v value) round(prec int) value {
+	if prec < 0 {
+		return v
+	}
+	lo, hi := v.bounds()
+	if lo != nil {
+		v.lo = roundRat(lo, prec, false)
+		v.iv.Lo = floatBelow(v.lo)
+	}
+	if hi != nil {
+		v.hi = roundRat(hi, prec, true)
+		v.iv.Hi = floatAbove(v.hi)
+	}
+	return v
+}
+
+// result rounds v outward to prec fractional digits and returns it as an Interval
+// of the floating point numbers nearest to the decimal bounds, so they are shown
+// as the decimals they stand for. With negative prec the bounds enclose the exact ones.
+func (v value) result(prec int) Interval {
+	if prec < 0 {
+		return v.iv
+	}
+	v = v.round(prec)
+	if v.lo != nil {
+		v.iv.Lo, _ = v.lo.Float64()
+	}
+	if v.hi != nil {
+		v.iv.Hi, _ = v.hi.Float64()
+	}
+	return v.iv
+}
+
+// roundRat rounds r to prec fractional digits, up if up is set and down otherwise.
+func roundRat(r *big.Rat, prec int, up bool) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(prec)), nil)
+	// Euclidean division by the positive denominator rounds down
+	q, m := new(big.Int).DivMod(new(big.Int).Mul(r.Num(), scale), r.Denom(), new(big.Int))
+	if up && m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return new(big.Rat).SetFrac(q, scale)
+}
+
+// floatBelow returns the greatest floating point number not above r.
+func floatBelow(r *big.Rat) float64 {
+	f, exact := r.Float64()
+	if exact || math.IsInf(f, -1) {
+		return f
+	}
+	if math.IsInf(f, 1) || new(big.Rat).SetFloat64(f).Cmp(r) > 0 {
+		return math.Nextafter(f, math.Inf(-1))
+	}
+	return f
+}
+
+// floatAbove returns the least floating point number not below r.
+func floatAbove(r *big.Rat) float64 {
+	return -floatBelow(new(big.Rat).Neg(r))
+}
@@ -0,0 +1,176 @@
+package wordfreq
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownAssociation is returned by ParseAssociation for unsupported names.
+var ErrUnknownAssociation = errors.New("unknown association measure")
+
+// Association is a measure of how strongly two words are associated,
+// collocates are sorted by it.
+type Association int
+
+// Association measures, TScore is the default.
+const (
+	TScore Association = iota // t-score, favours frequent collocates
+	PMI                       // pointwise mutual information, favours rare and exclusive ones
+	Dice                      // Dice coefficient
+)
+
+// associationNames - names of association measures for the command line
+var associationNames = map[Association]string{
+	TScore: "tscore",
+	PMI:    "pmi",
+	Dice:   "dice",
+}
+
+// String returns the name of the measure accepted by ParseAssociation.
+func (a Association) String() string {
+	if name, ok := associationNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("Association(%d)", int(a))
+}
+
+// ParseAssociation returns the association measure with the given name: tscore, pmi or dice.
+func ParseAssociation(name string) (Association, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for a, n := range associationNames {
+		if n == name {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q, use tscore, pmi or dice", ErrUnknownAssociation, name)
+}
+
+// Collocation is a word occurring near a node word with the strength of their association.
+//
+// With O co-occurrences, E = f(node)·f(word)·2·window/N of them expected if the words
+// were independent and N words in the text, PMI is log2(O/E), TScore is (O-E)/√O
+// and Dice is 2·min(O, f(node), f(word))/(f(node)+f(word)). A word may occur near
+// several occurrences of the node, so O may exceed the frequencies and is capped
+// by them for Dice to stay between 0 and 1. A t-score above 2 is usually taken as significant.
+type Collocation struct {
+	Word     Word
+	Together int // occurrences within the window of the node word
+	Count    int // occurrences in the text
+	PMI      float64
+	TScore   float64
+	Dice     float64
+}
+
+// score returns the measure a of the collocation.
+func (c Collocation) score(a Association) float64 {
+	switch a {
+	case PMI:
+		return c.PMI
+	case Dice:
+		return c.Dice
+	}
+	return c.TScore
+}
+
+// Cooccurrences counts how often words occur near each other: within window
+// words before or after one another. Stop words are left out before windows are
+// taken and words are normalized like by Counter, n-gram options are ignored.
+// The text is counted by the calling goroutine whatever the number of workers.
+// The zero value is not usable, create it with NewCooccurrences.
+type Cooccurrences struct {
+	opts   options
+	window int
+	counts map[Word]int
+	pairs  map[Word]map[Word]int // pairs[a][b] is the number of times b occurs near a
+	recent []Word                // the last window words for the text to follow
+	words  int
+	chunk  int
+}
+
+// NewCooccurrences returns an empty Cooccurrences with a window of the given
+// number of words on either side of a word, at least one.
+func NewCooccurrences(window int, opts ...Option) *Cooccurrences {
+	o := newOptions(opts)
+	// words are counted rather than n-grams, character n-grams of a word included
+	o.ngrams, o.chars = 1, false
+	return &Cooccurrences{
+		opts:   o,
+		window: max(window, 1),
+		counts: map[Word]int{},
+		pairs:  map[Word]map[Word]int{},
+		chunk:  DefaultChunkSize,
+	}
+}
+
+// Add counts the words of s as if they followed the words counted before.
+func (c *Cooccurrences) Add(s string) {
+	words, _ := c.opts.tokenizeForms(s)
+	for _, w := range words {
+		for _, near := range c.recent {
+			// a word repeated within the window is not its own collocate
+			if near != w {
+				addForm(c.pairs, w, near, 1)
+				addForm(c.pairs, near, w, 1)
+			}
+		}
+		c.recent = append(c.recent, w)
+		if len(c.recent) > c.window {
+			c.recent = c.recent[1:]
+		}
+		c.counts[w]++
+		c.words++
+	}
+}
+
+// ReadFrom counts the words read from r until EOF like Counter.ReadFrom
+// and returns the number of bytes read.
+func (c *Cooccurrences) ReadFrom(r io.Reader) (int64, error) {
+	return readChunks(r, c.chunk, c.Add)
+}
+
+// Total returns the number of words counted.
+func (c *Cooccurrences) Total() int {
+	return c.words
+}
+
+// Collocates returns at most k words, none if k is not positive, occurring near the node word at least
+// minTogether times, the most strongly associated with it by the measure by first.
+// Words with equal scores are ordered lexicographically. The node is split and
+// normalized like the text, so "Errors" finds the collocates of "error" with
+// an English stemmer; if that does not give a single word nothing is found.
+func (c *Cooccurrences) Collocates(node string, k int, by Association, minTogether int) []Collocation {
+	words, _ := c.opts.tokenizeForms(node)
+	if len(words) != 1 {
+		return []Collocation{}
+	}
+	w := words[0]
+	span := float64(2 * c.window)
+	result := make([]Collocation, 0, len(c.pairs[w]))
+	for near, together := range c.pairs[w] {
+		if together < minTogether {
+			continue
+		}
+		o := float64(together)
+		expected := float64(c.counts[w]) * float64(c.counts[near]) * span / float64(c.words)
+		result = append(result, Collocation{
+			Word:     near,
+			Together: together,
+			Count:    c.counts[near],
+			PMI:      math.Log2(o / expected),
+			TScore:   (o - expected) / math.Sqrt(o),
+			Dice:     2 * float64(min(together, c.counts[w], c.counts[near])) / float64(c.counts[w]+c.counts[near]),
+		})
+	}
+	slices.SortFunc(result, func(a, b Collocation) int {
+		if c := cmp.Compare(b.score(by), a.score(by)); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Word), string(b.Word))
+	})
+	return result[:min(max(k, 0), len(result))]
+}
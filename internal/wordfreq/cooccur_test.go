@@ -0,0 +1,135 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"strings"
+	"testing"
+)
+
+// ExampleCooccurrences_Collocates
+func ExampleCooccurrences_Collocates() {
+	c := NewCooccurrences(1)
+	c.Add("disk error on boot, fatal error: disk full. Boot ok. Fatal error again")
+	for _, w := range c.Collocates("Error", 3, TScore, 2) {
+		fmt.Printf("%s %d %d %.2f\n", w.Word, w.Together, w.Count, w.TScore)
+	}
+	// Output:
+	// disk 2 2 0.76
+	// fatal 2 2 0.76
+}
+
+func TestParseAssociation(t *testing.T) {
+	for _, a := range []Association{TScore, PMI, Dice} {
+		if got, err := ParseAssociation(a.String()); got != a || err != nil {
+			t.Errorf("Expected %v, got %v, %v", a, got, err)
+		}
+	}
+	if _, err := ParseAssociation("ll"); !errors.Is(err, ErrUnknownAssociation) {
+		t.Errorf("Expected %v, got %v", ErrUnknownAssociation, err)
+	}
+}
+
+func TestCooccurrences_Add(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   int
+		text     string
+		expected map[Word]int
+	}{
+		{"neighbours", 1, "a b c a", map[Word]int{"b": 1, "c": 1}},
+		{"window", 2, "a b c a", map[Word]int{"b": 2, "c": 2}},
+		{"both sides", 1, "b a c", map[Word]int{"b": 1, "c": 1}},
+		{"repeated word", 3, "a a b", map[Word]int{"b": 2}},
+		{"zero window", 0, "a b c", map[Word]int{"b": 1}},
+		{"alone", 2, "a", map[Word]int{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewCooccurrences(d.window)
+			c.Add(d.text)
+			got := map[Word]int{}
+			for _, w := range c.Collocates("a", 10, TScore, 0) {
+				got[w.Word] = w.Together
+			}
+			if !maps.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestCooccurrences_stream(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog and the quick cat"
+	whole := NewCooccurrences(3)
+	whole.Add(text)
+	parts := NewCooccurrences(3)
+	for w := range strings.FieldsSeq(text) {
+		parts.Add(w)
+	}
+	read := NewCooccurrences(3)
+	read.chunk = 8
+	if _, err := read.ReadFrom(strings.NewReader(text)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fmt.Sprint(whole.Collocates("the", 20, PMI, 1))
+	for _, c := range []*Cooccurrences{parts, read} {
+		if got := fmt.Sprint(c.Collocates("the", 20, PMI, 1)); got != expected {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+		if c.Total() != 13 {
+			t.Errorf("Expected 13, got %d", c.Total())
+		}
+	}
+}
+
+func TestCooccurrences_Collocates(t *testing.T) {
+	c := NewCooccurrences(1, WithStopWords(StopWords{"the": {}}), WithNormalizer(EnglishStemmer{}))
+	c.Add("errors in the log, an error of the disk and the disk errors")
+	got := c.Collocates("Errors", 10, Dice, 1)
+	expected := []Collocation{
+		{Word: "an", Together: 1, Count: 1, Dice: 0.5},
+		{Word: "in", Together: 1, Count: 1, Dice: 0.5},
+		{Word: "of", Together: 1, Count: 1, Dice: 0.5},
+		{Word: "disk", Together: 1, Count: 2, Dice: 0.4},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i, e := range expected {
+		if got[i].Word != e.Word || got[i].Together != e.Together || got[i].Count != e.Count || math.Abs(got[i].Dice-e.Dice) > 1e-9 {
+			t.Errorf("Expected %v, got %v", e, got[i])
+		}
+	}
+	for _, node := range []string{"the", "disk errors", "missing"} {
+		if got := c.Collocates(node, 10, Dice, 0); len(got) != 0 {
+			t.Errorf("%q: Expected no collocates, got %v", node, got)
+		}
+	}
+	for _, opt := range []Option{WithNGrams(2), WithCharNGrams(3)} {
+		ngrams := NewCooccurrences(1, WithStopWords(StopWords{"the": {}}), WithNormalizer(EnglishStemmer{}), opt)
+		ngrams.Add("errors in the log, an error of the disk and the disk errors")
+		if got, expected := fmt.Sprint(ngrams.Collocates("Errors", 10, Dice, 1)), fmt.Sprint(got); got != expected {
+			t.Errorf("Expected n-gram options to be ignored, got %v", got)
+		}
+	}
+	if got := c.Collocates("error", -1, Dice, 0); len(got) != 0 {
+		t.Errorf("Expected no collocates for negative k, got %v", got)
+	}
+}
+
+func TestCollocation_scores(t *testing.T) {
+	c := NewCooccurrences(1)
+	c.Add("a b a b c d")
+	got := c.Collocates("a", 1, PMI, 0)[0]
+	// b occurs 3 times near a, 1.33 times expected of 2·2·2 pairs among 6 words,
+	// for Dice not more often than either word occurs
+	expected := Collocation{Word: "b", Together: 3, Count: 2, PMI: math.Log2(3 / (8.0 / 6)), TScore: (3 - 8.0/6) / math.Sqrt(3), Dice: 1}
+	if got.Word != expected.Word || got.Together != expected.Together ||
+		math.Abs(got.PMI-expected.PMI) > 1e-9 || math.Abs(got.TScore-expected.TScore) > 1e-9 || got.Dice != expected.Dice {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,193 @@
+package wordfreq
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultChunkSize is the size of the buffer Counter reads text with.
+const DefaultChunkSize = 64 << 10
+
+// Counter counts words of a text which may come in parts, from strings or
+// from readers of any size. The zero value is not usable, create it with NewCounter.
+type Counter struct {
+	opts  options
+	stats map[Word]wordStat
+	forms map[Word]map[Word]int // forms merged into every word by the normalizer
+	grams ngramStream           // last words of the text for word n-grams
+	words int                   // number of words counted so far
+	chunk int                   // buffer size of ReadFrom
+}
+
+// NewCounter returns an empty Counter with the given options.
+func NewCounter(opts ...Option) *Counter {
+	o := newOptions(opts)
+	return &Counter{
+		opts:  o,
+		stats: map[Word]wordStat{},
+		forms: map[Word]map[Word]int{},
+		grams: ngramStream{n: o.ngramWords()},
+		chunk: DefaultChunkSize,
+	}
+}
+
+// Add counts the words of s as if they followed the words counted before.
+// With several workers (see WithWorkers) a long s is counted in parallel like by ReadFrom.
+func (c *Counter) Add(s string) {
+	if c.opts.workers > 1 && len(s) > c.chunk {
+		c.ReadFrom(strings.NewReader(s))
+		return
+	}
+	c.count(s)
+}
+
+// count counts the words of s in the calling goroutine.
+func (c *Counter) count(s string) {
+	words, forms := c.grams.next(c.opts.tokenizeForms(s))
+	for i, w := range words {
+		if forms != nil {
+			addForm(c.forms, w, forms[i], 1)
+		}
+		st, ok := c.stats[w]
+		if !ok {
+			st.first = c.words
+		}
+		st.count++
+		st.last = c.words
+		c.stats[w] = st
+		c.words++
+	}
+}
+
+// ReadFrom counts the words read from r until EOF and returns the number of bytes read.
+// The words read before an error are counted too.
+//
+// The text is read in chunks of DefaultChunkSize bytes cut at whitespace,
+// so memory used for the text does not depend on its size. The tokenizer
+// must not join words across whitespace, which holds for the tokenizers of the
+// package. A word longer than a chunk is counted in parts.
+//
+// With several workers (see WithWorkers) chunks are counted in parallel,
+// the result is the same as with one.
+func (c *Counter) ReadFrom(r io.Reader) (int64, error) {
+	if c.opts.workers > 1 {
+		return c.readParallel(r)
+	}
+	return readChunks(r, c.chunk, c.count)
+}
+
+// readChunks reads r until EOF in chunks of at most size bytes cut at whitespace
+// and passes them to emit. It returns the number of bytes read.
+func readChunks(r io.Reader, size int, emit func(string)) (int64, error) {
+	buf := make([]byte, size)
+	var total int64
+	start := 0 // bytes of an unfinished word carried over from the previous chunk
+	for {
+		n, err := r.Read(buf[start:])
+		total += int64(n)
+		end := start + n
+		if err != nil {
+			if end > 0 {
+				emit(string(buf[:end]))
+			}
+			if errors.Is(err, io.EOF) {
+				return total, nil
+			}
+			return total, err
+		}
+		cut := lastSpaceEnd(buf[:end])
+		if cut == 0 {
+			if end < len(buf) {
+				start = end
+				continue
+			}
+			cut = lastRuneEnd(buf[:end])
+		}
+		emit(string(buf[:cut]))
+		start = copy(buf, buf[cut:end])
+	}
+}
+
+// lastSpaceEnd returns the index after the last whitespace character of b, 0 if there is none.
+func lastSpaceEnd(b []byte) int {
+	for i := len(b); i > 0; {
+		r, size := utf8.DecodeLastRune(b[:i])
+		if unicode.IsSpace(r) {
+			return i
+		}
+		i -= size
+	}
+	return 0
+}
+
+// lastRuneEnd returns the length of b without an incomplete UTF-8 sequence at its end.
+func lastRuneEnd(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
+
+// Total returns the number of words, or n-grams, counted.
+func (c *Counter) Total() int {
+	return c.words
+}
+
+// Counts returns the number of occurrences of every word counted.
+func (c *Counter) Counts() map[Word]int {
+	result := make(map[Word]int, len(c.stats))
+	for w, st := range c.stats {
+		result[w] = st.count
+	}
+	return result
+}
+
+// Forms returns the forms of the text merged into the word w by the normalizer
+// (see WithNormalizer) with the number of occurrences of each, nil without a normalizer
+// or if w has not been counted.
+func (c *Counter) Forms(w Word) map[Word]int {
+	if c.forms[w] == nil {
+		return nil
+	}
+	return maps.Clone(c.forms[w])
+}
+
+// addForm adds n occurrences of the form f of the word w to forms.
+func addForm(forms map[Word]map[Word]int, w, f Word, n int) {
+	m, ok := forms[w]
+	if !ok {
+		m = map[Word]int{}
+		forms[w] = m
+	}
+	m[f] += n
+}
+
+// Top returns the k most frequent words counted, all of them if k exceeds their number.
+// Words with equal frequency are ordered by the tie-breaking rule of the options.
+// Only the k words are sorted, not the whole vocabulary.
+func (c *Counter) Top(k int) WordSlice {
+	return words(c.top(k))
+}
+
+// TopEntries returns the k most frequent words like Top with their counts,
+// frequencies relative to Total and ranks.
+func (c *Counter) TopEntries(k int) EntrySlice {
+	return newEntries(c.top(k), c.words)
+}
+
+// top returns the k most frequent words with their stats.
+func (c *Counter) top(k int) []ranked {
+	if k < len(c.stats) {
+		return topRanked(c.stats, c.opts.tieBreak, k)
+	}
+	return rankAll(c.stats, c.opts.tieBreak)
+}
@@ -0,0 +1,115 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+// ExampleCounter
+func ExampleCounter() {
+	c := NewCounter()
+	c.ReadFrom(strings.NewReader("the cat\nsat on the mat\n"))
+	c.Add("the end")
+	fmt.Println(c.Top(2), c.Total())
+	// Output: [the cat] 8
+}
+
+func TestCounter_ReadFrom(t *testing.T) {
+	text := strings.Repeat("Ёлка и café, don't stop—well-known words!\n", 50) + "tail"
+	expected := GetWordsMap(text)
+	tests := []struct {
+		name  string
+		chunk int
+		wrap  func(r io.Reader) io.Reader
+	}{
+		{"default chunk", DefaultChunkSize, nil},
+		{"longest word", 18, nil},
+		{"odd chunk", 23, nil},
+		{"one byte reads", 20, iotest.OneByteReader},
+		{"half reads", 20, iotest.HalfReader},
+		{"data with EOF", 32, iotest.DataErrReader},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewCounter()
+			c.chunk = d.chunk
+			var r io.Reader = strings.NewReader(text)
+			if d.wrap != nil {
+				r = d.wrap(r)
+			}
+			n, err := c.ReadFrom(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != int64(len(text)) {
+				t.Errorf("Expected %d bytes, got %d", len(text), n)
+			}
+			if got := c.Counts(); !maps.Equal(got, expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestCounter_ReadFromError(t *testing.T) {
+	errRead := errors.New("read failed")
+	c := NewCounter()
+	r := io.MultiReader(strings.NewReader("a b c"), iotest.ErrReader(errRead))
+	if _, err := c.ReadFrom(r); !errors.Is(err, errRead) {
+		t.Errorf("Expected %v, got %v", errRead, err)
+	}
+	if c.Total() != 3 {
+		t.Errorf("Expected the words read before the error to be counted, got %d", c.Total())
+	}
+}
+
+func TestCounter_LongWord(t *testing.T) {
+	c := NewCounter()
+	c.chunk = 8
+	c.ReadFrom(strings.NewReader("ёёёёёёёёёё"))
+	runes := 0
+	for w, n := range c.Counts() {
+		if !utf8.ValidString(string(w)) {
+			t.Errorf("Expected whole runes, got %q", w)
+		}
+		runes += n * utf8.RuneCountInString(string(w))
+	}
+	if runes != 10 {
+		t.Errorf("Expected 10 runes in parts of the word, got %d", runes)
+	}
+}
+
+func TestCounter_Top(t *testing.T) {
+	c := NewCounter(WithTieBreak(FirstOccurrence))
+	c.Add("b a")
+	c.Add("a b c")
+	tests := []struct {
+		name     string
+		k        int
+		expected WordSlice
+	}{
+		{"two", 2, WordSlice{"b", "a"}},
+		{"all", 10, WordSlice{"b", "a", "c"}},
+		{"zero", 0, WordSlice{}},
+		{"negative", -1, WordSlice{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := c.Top(d.k); !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+	if c.Total() != 5 {
+		t.Errorf("Expected 5, got %d", c.Total())
+	}
+}
@@ -0,0 +1,136 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrUnknownColumn is returned by ParseColumns for unsupported names.
+var ErrUnknownColumn = errors.New("unknown column")
+
+// Entry is a word of a ranking with its statistics.
+// The JSON fields have the names and the order of the other formats, see Format.
+type Entry struct {
+	Rank  int     `json:"rank"` // 1 for the most frequent words, words with equal counts share a rank
+	Word  Word    `json:"word"`
+	Count int     `json:"count"`
+	Freq  float64 `json:"freq"` // share of all words counted, from 0 to 1
+}
+
+// EntrySlice represents a ranking, the most frequent words first.
+type EntrySlice []Entry
+
+// newEntries returns the entries of the ranked words out of total words counted.
+// Ranks are competition ranks: after two words of rank 1 comes rank 3.
+func newEntries(words []ranked, total int) EntrySlice {
+	result := make(EntrySlice, len(words))
+	for i, r := range words {
+		e := Entry{Rank: i + 1, Word: r.word, Count: r.stat.count}
+		if total > 0 {
+			e.Freq = float64(r.stat.count) / float64(total)
+		}
+		if i > 0 && result[i-1].Count == e.Count {
+			e.Rank = result[i-1].Rank
+		}
+		result[i] = e
+	}
+	return result
+}
+
+// GetResultEntries is like GetResultWordsSlice but returns the words with their
+// counts, relative frequencies and ranks.
+func GetResultEntries(s string, k int, opts ...Option) EntrySlice {
+	c := NewCounter(opts...)
+	c.Add(s)
+	return c.TopEntries(k)
+}
+
+// Words returns the words of the entries.
+func (e EntrySlice) Words() WordSlice {
+	result := make(WordSlice, len(e))
+	for i, entry := range e {
+		result[i] = entry.Word
+	}
+	return result
+}
+
+// Columns is a set of statistics printed next to words by EntrySlice.Print.
+type Columns int
+
+// Columns of EntrySlice.Print, printed in this order around the word:
+// rank, word, count, frequency.
+const (
+	ColumnRank  Columns = 1 << iota // rank of the word
+	ColumnCount                     // number of occurrences
+	ColumnFreq                      // frequency in percent
+
+	AllColumns = ColumnRank | ColumnCount | ColumnFreq
+)
+
+// columnNames - names of columns for the command line
+var columnNames = []struct {
+	column Columns
+	name   string
+}{
+	{ColumnRank, "rank"},
+	{ColumnCount, "count"},
+	{ColumnFreq, "freq"},
+}
+
+// ParseColumns returns the columns of a comma-separated list of names:
+// rank, count, freq or all.
+func ParseColumns(names string) (Columns, error) {
+	var result Columns
+	for name := range strings.SplitSeq(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			result |= AllColumns
+			continue
+		}
+		found := false
+		for _, c := range columnNames {
+			if c.name == name {
+				result |= c.column
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: %q, use rank, count, freq or all", ErrUnknownColumn, name)
+		}
+	}
+	return result, nil
+}
+
+// Print prints an entry per line with the given columns separated by tabs,
+// e.g. "1\tcc\t4\t36.36%" for AllColumns. Without columns it prints the words
+// in one line like WordSlice.PrintWords.
+func (e EntrySlice) Print(writer io.Writer, cols Columns) {
+	if cols == 0 {
+		e.Words().PrintWords(writer)
+		return
+	}
+	for _, entry := range e {
+		fmt.Fprintln(writer, entry.Format(cols))
+	}
+}
+
+// Format returns the word and the given columns of the entry separated by tabs.
+func (e Entry) Format(cols Columns) string {
+	fields := make([]string, 0, 4)
+	if cols&ColumnRank != 0 {
+		fields = append(fields, fmt.Sprint(e.Rank))
+	}
+	fields = append(fields, string(e.Word))
+	if cols&ColumnCount != 0 {
+		fields = append(fields, fmt.Sprint(e.Count))
+	}
+	if cols&ColumnFreq != 0 {
+		fields = append(fields, fmt.Sprintf("%.2f%%", e.Freq*100))
+	}
+	return strings.Join(fields, "\t")
+}
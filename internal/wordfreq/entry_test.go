@@ -0,0 +1,95 @@
+package wordfreq
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+// ExampleEntrySlice_Print
+func ExampleEntrySlice_Print() {
+	GetResultEntries("aa bb cc aa cc cc cc aa ab ac bb", 3).Print(os.Stdout, AllColumns)
+	// Output:
+	// 1	cc	4	36.36%
+	// 2	aa	3	27.27%
+	// 3	bb	2	18.18%
+}
+
+func TestGetResultEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		k        int
+		expected EntrySlice
+	}{
+		{"distinct counts", "b a b c b a", 2, EntrySlice{{1, "b", 3, 0.5}, {2, "a", 2, 1.0 / 3}}},
+		{"shared ranks", "a b c c d", 4, EntrySlice{{1, "c", 2, 0.4}, {2, "a", 1, 0.2}, {2, "b", 1, 0.2}, {2, "d", 1, 0.2}}},
+		{"rank after a tie", "a a b b c", 3, EntrySlice{{1, "a", 2, 0.4}, {1, "b", 2, 0.4}, {3, "c", 1, 0.2}}},
+		{"k exceeds", "x", 5, EntrySlice{{1, "x", 1, 1}}},
+		{"empty", "", 3, EntrySlice{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := GetResultEntries(d.input, d.k)
+			if !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+			if words := GetResultWordsSlice(d.input, d.k); !slices.Equal(got.Words(), words) {
+				t.Errorf("Expected %v, got %v", words, got.Words())
+			}
+		})
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Columns
+		err      error
+	}{
+		{"one", "count", ColumnCount, nil},
+		{"several", " Freq, rank ", ColumnFreq | ColumnRank, nil},
+		{"all", "all", AllColumns, nil},
+		{"none", "", 0, nil},
+		{"unknown", "count,size", 0, ErrUnknownColumn},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := ParseColumns(d.input)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestEntrySlice_Print(t *testing.T) {
+	entries := EntrySlice{{1, "cc", 4, 0.5}, {1, "aa", 4, 0.5}}
+	tests := []struct {
+		name     string
+		cols     Columns
+		expected string
+	}{
+		{"words only", 0, "cc aa\n"},
+		{"count", ColumnCount, "cc\t4\naa\t4\n"},
+		{"rank and freq", ColumnRank | ColumnFreq, "1\tcc\t50.00%\n1\taa\t50.00%\n"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			var b bytes.Buffer
+			entries.Print(&b, d.cols)
+			if b.String() != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package wordfreq
+
+import (
+	"regexp"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter decides whether a word is counted.
+type Filter interface {
+	Keep(w Word) bool
+}
+
+// FilterFunc is an adapter to use an ordinary function as a Filter.
+type FilterFunc func(w Word) bool
+
+// Keep calls f(w).
+func (f FilterFunc) Keep(w Word) bool {
+	return f(w)
+}
+
+// IncludeFilter keeps only the words matching re. The pattern is not anchored,
+// use ^ and $ to match whole words.
+func IncludeFilter(re *regexp.Regexp) Filter {
+	return FilterFunc(func(w Word) bool {
+		return re.MatchString(string(w))
+	})
+}
+
+// ExcludeFilter leaves out the words matching re. The pattern is not anchored,
+// use ^ and $ to match whole words.
+func ExcludeFilter(re *regexp.Regexp) Filter {
+	return FilterFunc(func(w Word) bool {
+		return !re.MatchString(string(w))
+	})
+}
+
+// LengthFilter keeps the words of min to max characters, max of 0 or less means no limit.
+func LengthFilter(min, max int) Filter {
+	return FilterFunc(func(w Word) bool {
+		n := utf8.RuneCountInString(string(w))
+		return n >= min && (max <= 0 || n <= max)
+	})
+}
+
+// NumericFilter leaves out numbers: words with digits and no letters,
+// like "42", "3.14" or "2024-01-01".
+var NumericFilter Filter = FilterFunc(func(w Word) bool {
+	number := false
+	for _, r := range string(w) {
+		if unicode.IsLetter(r) {
+			return true
+		}
+		number = number || unicode.IsNumber(r)
+	}
+	return !number
+})
+
+// URLFilter leaves out URLs, words starting with a scheme like "https://" or with "www.".
+// UnicodeTokenizer keeps URLs whole with Links set, otherwise it splits them into words
+// the filter does not tell from others; with FieldsTokenizer punctuation around them is ignored.
+var URLFilter Filter = FilterFunc(func(w Word) bool {
+	return !urlPattern.MatchString(trimLink(string(w)))
+})
+
+// EmailFilter leaves out email addresses. Like for URLFilter, UnicodeTokenizer
+// must have Links set to keep them whole.
+var EmailFilter Filter = FilterFunc(func(w Word) bool {
+	return !emailPattern.MatchString(trimLink(string(w)))
+})
+
+// filter returns the words every filter keeps, reusing words.
+func filter(words []Word, filters []Filter) []Word {
+	if len(filters) == 0 {
+		return words
+	}
+	return slices.DeleteFunc(words, func(w Word) bool {
+		for _, f := range filters {
+			if !f.Keep(w) {
+				return true
+			}
+		}
+		return false
+	})
+}
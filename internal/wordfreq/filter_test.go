@@ -0,0 +1,77 @@
+package wordfreq
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"testing"
+)
+
+// ExampleWithFilters
+func ExampleWithFilters() {
+	text := "Mail bob@example.com or see https://example.com/docs, 42 times, for the 2nd time in 2024."
+	fmt.Println(GetResultWordsSlice(text, 10,
+		WithTokenizer(UnicodeTokenizer{Links: true}), WithFilters(URLFilter, EmailFilter, NumericFilter, LengthFilter(3, 0))))
+	// Output: [2nd for mail see the time times]
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		kept     []Word
+		excluded []Word
+	}{
+		{"include", IncludeFilter(regexp.MustCompile(`^go`)), []Word{"go", "gopher"}, []Word{"ago", "rust"}},
+		{"exclude", ExcludeFilter(regexp.MustCompile(`ing$`)), []Word{"run", "ingot"}, []Word{"running", "ing"}},
+		{"min length", LengthFilter(3, 0), []Word{"cat", "ёлка", "well-known"}, []Word{"a", "ok", "ёл"}},
+		{"length range", LengthFilter(2, 3), []Word{"ok", "cat"}, []Word{"a", "ёлка"}},
+		{"numeric", NumericFilter, []Word{"2nd", "covid19", "-", "½x"}, []Word{"42", "3.14", "2024-01-01", "½", "٣"}},
+		{"url", URLFilter, []Word{"example.com", "http", "www", "bob@example.com"},
+			[]Word{"https://example.com/a?b=c", "ftp://host", "www.example.com", "(https://go.dev).", "HTTP://GO.DEV"}},
+		{"email", EmailFilter, []Word{"@", "bob@", "a@b", "https://example.com"},
+			[]Word{"bob@example.com", "first.last+tag@mail.example.org", "<иван@почта.рф>,"}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			for _, w := range d.kept {
+				if !d.filter.Keep(w) {
+					t.Errorf("Expected %q kept", w)
+				}
+			}
+			for _, w := range d.excluded {
+				if d.filter.Keep(w) {
+					t.Errorf("Expected %q left out", w)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWordsMap_WithFilters(t *testing.T) {
+	text := "The cat and the cats, 3 cats at www.cats.com"
+	links := WithTokenizer(UnicodeTokenizer{Links: true})
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected map[Word]int
+	}{
+		{"no filters", nil, map[Word]int{"the": 2, "cat": 1, "and": 1, "cats": 2, "3": 1, "at": 1, "www.cats.com": 1}},
+		{"pipeline", []Option{WithFilters(URLFilter, NumericFilter), WithFilters(LengthFilter(3, 0))},
+			map[Word]int{"the": 2, "cat": 1, "and": 1, "cats": 2}},
+		{"with stop words and stems", []Option{
+			WithStopWords(StopWords{"the": {}}),
+			WithFilters(IncludeFilter(regexp.MustCompile(`^cats?$`))),
+			WithNormalizer(EnglishStemmer{}),
+		}, map[Word]int{"cat": 3}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := GetWordsMap(text, append([]Option{links}, d.opts...)...); !maps.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package wordfreq
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownFormat is returned by ParseFormat for unsupported names.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Format is an output format of EntrySlice.Write.
+//
+// All formats but Text have the fields rank, word, count and freq,
+// freq being the share of all words from 0 to 1. The names and the order
+// of the fields do not change, so the output may be consumed by other programs.
+type Format int
+
+// Output formats, Text is the default.
+const (
+	Text     Format = iota // words in one line or the columns of EntrySlice.Print
+	JSON                   // an array of objects
+	NDJSON                 // an object per line
+	CSV                    // comma-separated values with a header line
+	TSV                    // tab-separated values with a header line
+	Markdown               // a table
+)
+
+// formatNames - names of formats for the command line
+var formatNames = map[Format]string{
+	Text:     "text",
+	JSON:     "json",
+	NDJSON:   "ndjson",
+	CSV:      "csv",
+	TSV:      "tsv",
+	Markdown: "markdown",
+}
+
+// fieldNames - fields of the machine-readable formats
+var fieldNames = []string{"rank", "word", "count", "freq"}
+
+// String returns the name of the format accepted by ParseFormat.
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the format with the given name:
+// text, json, ndjson, csv, tsv or markdown (md).
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "md" {
+		return Markdown, nil
+	}
+	for f, n := range formatNames {
+		if n == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q, use text, json, ndjson, csv, tsv or markdown", ErrUnknownFormat, name)
+}
+
+// Write writes the entries in the format f. The columns are used by Text only,
+// the other formats always have all the fields.
+func (e EntrySlice) Write(writer io.Writer, f Format, cols Columns) error {
+	switch f {
+	case Text:
+		e.Print(writer, cols)
+		return nil
+	case JSON:
+		if e == nil {
+			e = EntrySlice{}
+		}
+		enc := json.NewEncoder(writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	case NDJSON:
+		enc := json.NewEncoder(writer)
+		for _, entry := range e {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV, TSV:
+		w := csv.NewWriter(writer)
+		if f == TSV {
+			w.Comma = '\t'
+		}
+		w.Write(fieldNames)
+		for _, entry := range e {
+			w.Write(entry.fields())
+		}
+		w.Flush()
+		return w.Error()
+	case Markdown:
+		return e.writeMarkdown(writer)
+	}
+	return fmt.Errorf("%w: %v", ErrUnknownFormat, f)
+}
+
+// fields returns the values of the fields of the machine-readable formats.
+func (e Entry) fields() []string {
+	return []string{
+		strconv.Itoa(e.Rank),
+		string(e.Word),
+		strconv.Itoa(e.Count),
+		strconv.FormatFloat(e.Freq, 'g', -1, 64),
+	}
+}
+
+// writeMarkdown writes the entries as a Markdown table.
+func (e EntrySlice) writeMarkdown(writer io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| rank | word | count | freq |\n|---:|---|---:|---:|\n")
+	for _, entry := range e {
+		fields := entry.fields()
+		fields[1] = strings.ReplaceAll(fields[1], "|", `\|`)
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(fields, " | "))
+	}
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
@@ -0,0 +1,101 @@
+package wordfreq
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// ExampleEntrySlice_Write
+func ExampleEntrySlice_Write() {
+	GetResultEntries("aa bb cc aa cc cc cc aa ab ac bb", 2).Write(os.Stdout, CSV, 0)
+	// Output:
+	// rank,word,count,freq
+	// 1,cc,4,0.36363636363636365
+	// 2,aa,3,0.2727272727272727
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Format
+		err      error
+	}{
+		{"text", "text", Text, nil},
+		{"json", " JSON ", JSON, nil},
+		{"ndjson", "ndjson", NDJSON, nil},
+		{"tsv", "tsv", TSV, nil},
+		{"md", "md", Markdown, nil},
+		{"unknown", "xml", 0, ErrUnknownFormat},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := ParseFormat(d.input)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+			if err == nil && d.name != "md" {
+				if back, _ := ParseFormat(got.String()); back != got {
+					t.Errorf("Expected %v, got %v", got, back)
+				}
+			}
+		})
+	}
+}
+
+func TestEntrySlice_Write(t *testing.T) {
+	entries := EntrySlice{{1, "a|b", 3, 0.75}, {2, `say "hi"`, 1, 0.25}}
+	tests := []struct {
+		name     string
+		entries  EntrySlice
+		format   Format
+		cols     Columns
+		expected string
+	}{
+		{"text", entries, Text, ColumnCount, "a|b\t3\nsay \"hi\"\t1\n"},
+		{"json", entries, JSON, 0, `[
+  {
+    "rank": 1,
+    "word": "a|b",
+    "count": 3,
+    "freq": 0.75
+  },
+  {
+    "rank": 2,
+    "word": "say \"hi\"",
+    "count": 1,
+    "freq": 0.25
+  }
+]
+`},
+		{"empty json", nil, JSON, 0, "[]\n"},
+		{"ndjson", entries, NDJSON, AllColumns,
+			`{"rank":1,"word":"a|b","count":3,"freq":0.75}` + "\n" + `{"rank":2,"word":"say \"hi\"","count":1,"freq":0.25}` + "\n"},
+		{"empty ndjson", EntrySlice{}, NDJSON, 0, ""},
+		{"csv", entries, CSV, 0, "rank,word,count,freq\n1,a|b,3,0.75\n2,\"say \"\"hi\"\"\",1,0.25\n"},
+		{"tsv", entries, TSV, 0, "rank\tword\tcount\tfreq\n1\ta|b\t3\t0.75\n2\t\"say \"\"hi\"\"\"\t1\t0.25\n"},
+		{"markdown", entries, Markdown, 0,
+			"| rank | word | count | freq |\n|---:|---|---:|---:|\n| 1 | a\\|b | 3 | 0.75 |\n| 2 | say \"hi\" | 1 | 0.25 |\n"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := d.entries.Write(&b, d.format, d.cols); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.String() != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, b.String())
+			}
+		})
+	}
+	if err := entries.Write(&bytes.Buffer{}, Format(42), 0); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected %v, got %v", ErrUnknownFormat, err)
+	}
+}
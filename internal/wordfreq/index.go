@@ -0,0 +1,330 @@
+package wordfreq
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrCorruptIndex is returned when an index file cannot be parsed.
+var ErrCorruptIndex = errors.New("corrupt index")
+
+// indexHeader is the first line of an index file.
+const indexHeader = "# wordfreq index v1"
+
+// Index holds word counts of a growing collection of documents so that the
+// most frequent words may be found without reading the documents again.
+//
+// An index file is a text file of blocks, one per update, which is only
+// appended to. Every block starts with a line of the number of words and the
+// quoted names of the documents counted, then has a line per word: its count,
+// the positions of its first and last occurrences among the words of the block
+// and the quoted word, and ends with a line "end". Fields are separated by tabs:
+//
+//	# wordfreq index v1
+//	docs	5	"a.txt"
+//	2	0	3	"the"
+//	1	1	1	"cat"
+//	...
+//	end
+//
+// An append interrupted by a crash may leave an unfinished block at the end of the file:
+// ReadIndex ignores it and AppendIndex removes it before appending.
+//
+// Documents must be counted with the same tokenizer and options for
+// the counts to be comparable, the index does not check it.
+// The zero value is not usable, create it with NewIndex or ReadIndex.
+type Index struct {
+	opts       options
+	stats      map[Word]wordStat
+	words      int
+	docs       []string
+	incomplete bool // whether an unfinished block was ignored by ReadIndex
+}
+
+// NewIndex returns an empty index. Only the tie-breaking rule of the options is used.
+func NewIndex(opts ...Option) *Index {
+	return &Index{opts: newOptions(opts), stats: map[Word]wordStat{}}
+}
+
+// Add adds the words counted by c as the document name, following the documents before.
+func (x *Index) Add(name string, c *Counter) {
+	x.add([]string{name}, c.stats, c.words)
+}
+
+// Merge adds the documents of other following the documents of x.
+func (x *Index) Merge(other *Index) {
+	x.add(other.docs, other.stats, other.words)
+}
+
+// add adds the words of documents with positions local to them.
+func (x *Index) add(docs []string, stats map[Word]wordStat, words int) {
+	for w, st := range stats {
+		st.first += x.words
+		st.last += x.words
+		if old, ok := x.stats[w]; ok {
+			st.first = old.first
+			st.count += old.count
+		}
+		x.stats[w] = st
+	}
+	x.words += words
+	x.docs = append(x.docs, docs...)
+}
+
+// Total returns the number of words of all documents.
+func (x *Index) Total() int {
+	return x.words
+}
+
+// Documents returns the names of the documents in the order they were added.
+func (x *Index) Documents() []string {
+	return x.docs
+}
+
+// Incomplete reports whether ReadIndex ignored an unfinished block at the end of the file,
+// the documents of which are not in the index.
+func (x *Index) Incomplete() bool {
+	return x.incomplete
+}
+
+// Counts returns the number of occurrences of every word.
+func (x *Index) Counts() map[Word]int {
+	result := make(map[Word]int, len(x.stats))
+	for w, st := range x.stats {
+		result[w] = st.count
+	}
+	return result
+}
+
+// Top returns the k most frequent words like Counter.Top.
+func (x *Index) Top(k int) WordSlice {
+	return words(x.top(k))
+}
+
+// TopEntries returns the k most frequent words like Counter.TopEntries.
+func (x *Index) TopEntries(k int) EntrySlice {
+	return newEntries(x.top(k), x.words)
+}
+
+// top returns the k most frequent words with their stats.
+func (x *Index) top(k int) []ranked {
+	if k < len(x.stats) {
+		return topRanked(x.stats, x.opts.tieBreak, k)
+	}
+	return rankAll(x.stats, x.opts.tieBreak)
+}
+
+// WriteTo writes the whole index as a file of a single block.
+func (x *Index) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	b.WriteString(indexHeader + "\n")
+	writeBlock(&b, x.docs, x.stats, x.words)
+	return b.WriteTo(w)
+}
+
+// WriteFile replaces the named file with the whole index, see WriteTo.
+// The index is written to a temporary file first, so the file is never left half written.
+func (x *Index) WriteFile(name string) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := x.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// AppendIndex adds the words counted by c as the document doc to the named index file,
+// which is created if it does not exist. A block is appended, the file is not rewritten;
+// an unfinished block at its end is removed first. A file which does not start
+// with the header of an index is left as it is and ErrCorruptIndex is returned.
+// The file is not locked: appends to the same file must not run at the same time,
+// or their blocks may interleave.
+func AppendIndex(name, doc string, c *Counter) error {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	end, err := indexEnd(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := f.Truncate(end); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if end == 0 {
+		b.WriteString(indexHeader + "\n")
+	}
+	writeBlock(&b, []string{doc}, c.stats, c.words)
+	if _, err := f.WriteAt(b.Bytes(), end); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// indexEnd checks the header of an index file and returns the size of the file
+// without an unfinished block at its end, 0 for an empty file.
+func indexEnd(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return 0, err
+	}
+	const header, blockEnd = indexHeader + "\n", "\nend\n"
+	start := make([]byte, len(header))
+	if _, err := f.ReadAt(start, 0); err != nil || string(start) != header {
+		return 0, fmt.Errorf("%w: not a wordfreq index", ErrCorruptIndex)
+	}
+	size := info.Size()
+	tail := make([]byte, len(blockEnd))
+	if size == int64(len(header)) {
+		return size, nil
+	}
+	if _, err := f.ReadAt(tail, size-int64(len(tail))); err != nil {
+		return 0, err
+	}
+	if string(tail) == blockEnd {
+		return size, nil
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, size))
+	if err != nil {
+		return 0, err
+	}
+	// the newline of the header may end an empty index
+	if i := bytes.LastIndex(data, []byte(blockEnd)); i >= len(header)-1 {
+		return int64(i + len(blockEnd)), nil
+	}
+	return int64(len(header)), nil
+}
+
+// writeBlock writes a block of documents to b, the most frequent words first.
+func writeBlock(b *bytes.Buffer, docs []string, stats map[Word]wordStat, words int) {
+	b.WriteString("docs\t" + strconv.Itoa(words))
+	for _, d := range docs {
+		b.WriteString("\t" + strconv.Quote(d))
+	}
+	b.WriteString("\n")
+	for _, r := range rankAll(stats, FirstOccurrence) {
+		fmt.Fprintf(b, "%d\t%d\t%d\t%s\n", r.stat.count, r.stat.first, r.stat.last, strconv.Quote(string(r.word)))
+	}
+	b.WriteString("end\n")
+}
+
+// OpenIndex reads the named index file, see ReadIndex.
+func OpenIndex(name string, opts ...Option) (*Index, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	x, err := ReadIndex(f, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return x, nil
+}
+
+// ReadIndex reads an index file written by WriteTo or AppendIndex and adds
+// its blocks one after another. An unfinished block at the end of the file, left by
+// an interrupted append, is ignored, see Index.Incomplete.
+// Only the tie-breaking rule of the options is used.
+func ReadIndex(r io.Reader, opts ...Option) (*Index, error) {
+	x := NewIndex(opts...)
+	reader := bufio.NewReader(r)
+	line := 0
+	corrupt := func(msg string) error {
+		return fmt.Errorf("%w: line %d: %s", ErrCorruptIndex, line, msg)
+	}
+	var block *Index // the block being read
+	for {
+		text, err := reader.ReadString('\n')
+		if err == io.EOF {
+			// a last line without a newline was cut short
+			x.incomplete = text != "" || block != nil
+			return x, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line++
+		text = strings.TrimSuffix(text, "\n")
+		fields := strings.Split(text, "\t")
+		switch {
+		case line == 1:
+			if text != indexHeader {
+				return nil, corrupt("not a wordfreq index")
+			}
+		case block == nil:
+			if fields[0] != "docs" || len(fields) < 2 {
+				return nil, corrupt("expected the beginning of a block")
+			}
+			words, err := strconv.Atoi(fields[1])
+			if err != nil || words < 0 {
+				return nil, corrupt("invalid number of words")
+			}
+			block = NewIndex()
+			block.words = words
+			for _, q := range fields[2:] {
+				name, err := strconv.Unquote(q)
+				if err != nil {
+					return nil, corrupt("invalid document name")
+				}
+				block.docs = append(block.docs, name)
+			}
+		case text == "end":
+			x.Merge(block)
+			block = nil
+		default:
+			w, st, err := parseIndexEntry(fields, block.words)
+			if err != nil {
+				return nil, corrupt(err.Error())
+			}
+			if _, ok := block.stats[w]; ok {
+				return nil, corrupt(fmt.Sprintf("duplicate word %q", w))
+			}
+			block.stats[w] = st
+		}
+	}
+}
+
+// parseIndexEntry parses the fields of a word line of a block of the given number of words.
+func parseIndexEntry(fields []string, words int) (Word, wordStat, error) {
+	if len(fields) != 4 {
+		return "", wordStat{}, errors.New("expected count, first, last and word")
+	}
+	var nums [3]int
+	for i := range nums {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 {
+			return "", wordStat{}, fmt.Errorf("invalid number %q", fields[i])
+		}
+		nums[i] = n
+	}
+	st := wordStat{count: nums[0], first: nums[1], last: nums[2]}
+	if st.count == 0 || st.first > st.last || st.last >= words {
+		return "", wordStat{}, errors.New("inconsistent count or positions")
+	}
+	w, err := strconv.Unquote(fields[3])
+	if err != nil {
+		return "", wordStat{}, fmt.Errorf("invalid word %s", fields[3])
+	}
+	return Word(w), st, nil
+}
@@ -0,0 +1,170 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ExampleIndex
+func ExampleIndex() {
+	x := NewIndex()
+	for i, doc := range []string{"the cat sat", "the cat ran", "a dog ran"} {
+		c := NewCounter()
+		c.Add(doc)
+		x.Add(fmt.Sprint("doc", i+1), c)
+	}
+	x.WriteTo(os.Stdout)
+	// Output:
+	// # wordfreq index v1
+	// docs	9	"doc1"	"doc2"	"doc3"
+	// 2	0	3	"the"
+	// 2	1	4	"cat"
+	// 2	5	8	"ran"
+	// 1	2	2	"sat"
+	// 1	6	6	"a"
+	// 1	7	7	"dog"
+	// end
+}
+
+func TestAppendIndex(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "words.idx")
+	docs := []string{"Ёлка и ёлка", "the cat and\tthe \"dog\"", "", "ёлка cat"}
+	whole := NewCounter(WithTieBreak(LastOccurrence))
+	for i, doc := range docs {
+		c := NewCounter()
+		c.Add(doc)
+		whole.Add(doc)
+		if err := AppendIndex(name, fmt.Sprint("doc", i), c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	x, err := OpenIndex(name, WithTieBreak(LastOccurrence))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !maps.Equal(x.stats, whole.stats) || x.Total() != whole.Total() {
+		t.Errorf("Expected %v, got %v", whole.stats, x.stats)
+	}
+	if got, expected := x.Top(3), whole.Top(3); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := x.Documents(); !slices.Equal(got, []string{"doc0", "doc1", "doc2", "doc3"}) {
+		t.Errorf("Expected doc0 to doc3, got %v", got)
+	}
+
+	// a compacted index reads the same
+	if err := x.WriteFile(name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compacted, err := OpenIndex(name)
+	if err != nil || !maps.Equal(compacted.stats, x.stats) || !slices.Equal(compacted.docs, x.docs) {
+		t.Errorf("Expected the same index after compaction, got %v, %v", compacted, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(name)); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left, got %d files", len(entries))
+	}
+}
+
+func TestAppendIndex_unfinished(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCounter()
+	c.Add("a b a")
+	name := filepath.Join(dir, "words.idx")
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unfinished block", indexHeader + "\ndocs\t2\t\"cut\"\n1\t0\t0\t\"x\"\n"},
+		{"cut line after a block", indexHeader + "\ndocs\t3\t\"doc\"\n2\t0\t2\t\"a\"\n1\t1\t1\t\"b\"\nend\ndocs\t2\t"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if err := os.WriteFile(name, []byte(d.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := AppendIndex(name, "doc", c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			x, err := OpenIndex(name)
+			if err != nil || x.Incomplete() || slices.Contains(x.Documents(), "cut") {
+				t.Fatalf("Expected the unfinished block to be removed, got %v, %v", x, err)
+			}
+			if got := x.Counts()["a"]; got != 2*len(x.Documents()) {
+				t.Errorf("Expected %d, got %d", 2*len(x.Documents()), got)
+			}
+		})
+	}
+
+	other := filepath.Join(dir, "notes.txt")
+	os.WriteFile(other, []byte("not an index\n"), 0o644)
+	if err := AppendIndex(other, "doc", c); !errors.Is(err, ErrCorruptIndex) {
+		t.Errorf("Expected %v, got %v", ErrCorruptIndex, err)
+	}
+	if data, _ := os.ReadFile(other); string(data) != "not an index\n" {
+		t.Errorf("Expected the file to be left as it is, got %q", data)
+	}
+}
+
+func TestIndex_Merge(t *testing.T) {
+	a, b := NewIndex(), NewIndex()
+	ca, cb := NewCounter(), NewCounter()
+	ca.Add("x y x")
+	cb.Add("y z")
+	a.Add("a", ca)
+	b.Add("b", cb)
+	a.Merge(b)
+	expected := map[Word]int{"x": 2, "y": 2, "z": 1}
+	if got := a.Counts(); !maps.Equal(got, expected) || a.Total() != 5 {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := a.TopEntries(1); !slices.Equal(got, EntrySlice{{1, "x", 2, 0.4}}) {
+		t.Errorf("Expected x, got %v", got)
+	}
+}
+
+func TestReadIndex(t *testing.T) {
+	const header = indexHeader + "\n"
+	const block = "docs\t2\t\"a\"\n2\t0\t1\t\"w\"\nend\n"
+	tests := []struct {
+		name       string
+		input      string
+		counts     map[Word]int
+		incomplete bool
+		err        error
+	}{
+		{"empty index", header, map[Word]int{}, false, nil},
+		{"two blocks", header + block + "docs\t1\n1\t0\t0\t\"w\"\nend\n", map[Word]int{"w": 3}, false, nil},
+		{"no header", "docs\t1\nend\n", nil, false, ErrCorruptIndex},
+		{"unfinished block", header + block + "docs\t2\t\"b\"\n2\t0\t1\t\"v\"\n", map[Word]int{"w": 2}, true, nil},
+		{"cut line", header + block + "docs\t2\t\"b\"\n2\t0", map[Word]int{"w": 2}, true, nil},
+		{"cut end", header + block + "docs\t2\t\"b\"\n2\t0\t1\t\"v\"\nen", map[Word]int{"w": 2}, true, nil},
+		{"unfinished block in the middle", header + "docs\t2\t\"b\"\n" + block, nil, false, ErrCorruptIndex},
+		{"unquoted word", header + "docs\t1\n1\t0\t0\tw\nend\n", nil, false, ErrCorruptIndex},
+		{"position out of block", header + "docs\t1\n1\t0\t1\t\"w\"\nend\n", nil, false, ErrCorruptIndex},
+		{"negative count", header + "docs\t1\n-1\t0\t0\t\"w\"\nend\n", nil, false, ErrCorruptIndex},
+		{"duplicate word", header + "docs\t2\n1\t0\t0\t\"w\"\n1\t1\t1\t\"w\"\nend\n", nil, false, ErrCorruptIndex},
+		{"no block", header + "1\t0\t0\t\"w\"\n", nil, false, ErrCorruptIndex},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			x, err := ReadIndex(strings.NewReader(d.input))
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if err == nil && !maps.Equal(x.Counts(), d.counts) {
+				t.Errorf("Expected %v, got %v", d.counts, x.Counts())
+			}
+			if err == nil && x.Incomplete() != d.incomplete {
+				t.Errorf("Expected incomplete %v, got %v", d.incomplete, x.Incomplete())
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package wordfreq
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownStatistic is returned by ParseStatistic for unsupported names.
+var ErrUnknownStatistic = errors.New("unknown statistic")
+
+// Statistic is a keyness statistic words of a comparison are sorted by.
+type Statistic int
+
+// Keyness statistics, LogLikelihood is the default.
+const (
+	LogLikelihood Statistic = iota // log-likelihood G²
+	ChiSquared                     // Pearson's chi-squared
+	LogRatio                       // binary logarithm of the ratio of relative frequencies
+)
+
+// statisticNames - names of statistics for the command line
+var statisticNames = map[Statistic]string{
+	LogLikelihood: "ll",
+	ChiSquared:    "chi2",
+	LogRatio:      "logratio",
+}
+
+// String returns the name of the statistic accepted by ParseStatistic.
+func (s Statistic) String() string {
+	if name, ok := statisticNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Statistic(%d)", int(s))
+}
+
+// ParseStatistic returns the statistic with the given name: ll, chi2 or logratio.
+func ParseStatistic(name string) (Statistic, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for s, n := range statisticNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q, use ll, chi2 or logratio", ErrUnknownStatistic, name)
+}
+
+// Keyness tells how much more or less often a word occurs in a target text
+// than in a reference one, relative to the sizes of the texts.
+//
+// LogLikelihood and ChiSquared measure how significant the difference is and
+// do not tell its direction: G² of 3.84 means p < 0.05, 6.63 p < 0.01 and
+// 10.83 p < 0.001 with one degree of freedom. LogRatio measures how large it is:
+// 1 means twice as frequent in the target, -1 half as frequent. A word missing
+// in one of the texts is taken to occur there half a time for LogRatio.
+type Keyness struct {
+	Word          Word
+	Target        int // occurrences in the target text
+	Reference     int // occurrences in the reference text
+	LogLikelihood float64
+	ChiSquared    float64
+	LogRatio      float64
+}
+
+// Overused reports whether the word is relatively more frequent in the target text.
+func (k Keyness) Overused() bool {
+	return k.LogRatio > 0
+}
+
+// Underused reports whether the word is relatively less frequent in the target text.
+func (k Keyness) Underused() bool {
+	return k.LogRatio < 0
+}
+
+// Extremes returns at most k overused and at most k underused words of a result
+// of Compare, the most overused and the most underused ones first.
+// Words equally frequent in both texts are in neither.
+func Extremes(result []Keyness, k int) (overused, underused []Keyness) {
+	overused, underused = []Keyness{}, []Keyness{}
+	for _, w := range result {
+		if w.Overused() && len(overused) < k {
+			overused = append(overused, w)
+		}
+	}
+	for i := len(result) - 1; i >= 0 && len(underused) < k; i-- {
+		if result[i].Underused() {
+			underused = append(underused, result[i])
+		}
+	}
+	return overused, underused
+}
+
+// score returns the statistic s with the sign of the direction of the difference.
+func (k Keyness) score(s Statistic) float64 {
+	switch s {
+	case ChiSquared:
+		return math.Copysign(k.ChiSquared, k.LogRatio)
+	case LogRatio:
+		return k.LogRatio
+	}
+	return math.Copysign(k.LogLikelihood, k.LogRatio)
+}
+
+// Thresholds leave words out of a comparison.
+type Thresholds struct {
+	MinCount         int     // least number of occurrences in both texts together
+	MinLogLikelihood float64 // least G², e.g. 3.84 to keep differences significant at p < 0.05
+}
+
+// Compare computes the keyness of every word counted by target or reference
+// which passes the thresholds. Both counters must count words the same way.
+//
+// Words are sorted by the statistic with the sign of the direction of the difference:
+// the most overused words in the target text come first, the most underused ones last.
+// Words with equal scores are ordered lexicographically.
+func Compare(target, reference *Counter, by Statistic, th Thresholds) []Keyness {
+	return compare(target.Counts(), reference.Counts(), target.words, reference.words, by, th)
+}
+
+// compare compares the counts of words of a target text of targetWords words
+// with those of a reference text of referenceWords words like Compare.
+func compare(target, reference map[Word]int, targetWords, referenceWords int, by Statistic, th Thresholds) []Keyness {
+	if targetWords == 0 || referenceWords == 0 {
+		return []Keyness{}
+	}
+	result := make([]Keyness, 0, len(target))
+	add := func(w Word) {
+		k := keyness(w, target[w], reference[w], targetWords, referenceWords)
+		if k.Target+k.Reference >= th.MinCount && k.LogLikelihood >= th.MinLogLikelihood {
+			result = append(result, k)
+		}
+	}
+	for w := range target {
+		add(w)
+	}
+	for w := range reference {
+		if _, ok := target[w]; !ok {
+			add(w)
+		}
+	}
+	slices.SortFunc(result, func(a, b Keyness) int {
+		if c := cmp.Compare(b.score(by), a.score(by)); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Word), string(b.Word))
+	})
+	return result
+}
+
+// keyness computes the statistics of a word occurring a times in a target text
+// of c words and b times in a reference text of d words.
+func keyness(w Word, a, b, c, d int) Keyness {
+	fa, fb, fc, fd := float64(a), float64(b), float64(c), float64(d)
+	n := fc + fd
+	// expected numbers of occurrences if the word were equally frequent in both texts
+	e1, e2 := fc*(fa+fb)/n, fd*(fa+fb)/n
+	ll := 2 * (xlogy(fa, e1) + xlogy(fb, e2))
+	// chi-squared of the contingency table of the word and the other words
+	chi := 0.0
+	if rest := n - fa - fb; rest > 0 {
+		diff := fa*(fd-fb) - fb*(fc-fa)
+		chi = n * diff * diff / ((fa + fb) * rest * fc * fd)
+	}
+	return Keyness{
+		Word:          w,
+		Target:        a,
+		Reference:     b,
+		LogLikelihood: ll,
+		ChiSquared:    chi,
+		LogRatio:      math.Log2(max(fa, 0.5) / fc / (max(fb, 0.5) / fd)),
+	}
+}
+
+// xlogy returns x·ln(x/y), 0 when x is 0.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(x/y)
+}
@@ -0,0 +1,139 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// ExampleCompare
+func ExampleCompare() {
+	target, reference := NewCounter(), NewCounter()
+	target.Add("the cat sat on the cat mat with a cat")
+	reference.Add("the dog sat on the mat with a dog and the dog")
+	for _, k := range Compare(target, reference, LogRatio, Thresholds{MinCount: 3}) {
+		fmt.Printf("%s %d %d %.2f\n", k.Word, k.Target, k.Reference, k.LogRatio)
+	}
+	// Output:
+	// cat 3 0 2.85
+	// the 2 3 -0.32
+	// dog 0 3 -2.32
+}
+
+func Test_keyness(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b, c, d   int
+		ll, chi, lr  float64
+		expectedOver bool
+	}{
+		{"overused", 10, 10, 100, 1000, 22.138221829656096, 41.25, 3.321928094887362, true},
+		{"missing in target", 0, 20, 100, 1000, 3.81240719217299, 2.037037037037037, -2, false},
+		{"same frequency", 1, 10, 100, 1000, 0, 0, 0, false},
+		{"the only word", 5, 5, 5, 5, 0, 0, 0, false},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := keyness("w", d.a, d.b, d.c, d.d)
+			for _, v := range []struct {
+				name          string
+				got, expected float64
+			}{
+				{"G²", got.LogLikelihood, d.ll},
+				{"chi²", got.ChiSquared, d.chi},
+				{"log ratio", got.LogRatio, d.lr},
+			} {
+				if math.Abs(v.got-v.expected) > 1e-9 {
+					t.Errorf("%s: Expected %v, got %v", v.name, v.expected, v.got)
+				}
+			}
+			if got.Overused() != d.expectedOver {
+				t.Errorf("Expected %v, got %v", d.expectedOver, got.Overused())
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	target, reference, empty := NewCounter(), NewCounter(), NewCounter()
+	target.Add("a a a a b c c d")
+	reference.Add("a b b b b c c e e")
+	tests := []struct {
+		name     string
+		target   *Counter
+		by       Statistic
+		th       Thresholds
+		expected []Word
+	}{
+		{"by log-likelihood", target, LogLikelihood, Thresholds{}, []Word{"a", "d", "c", "b", "e"}},
+		{"by log ratio ties", target, LogRatio, Thresholds{}, []Word{"a", "d", "c", "b", "e"}},
+		{"min count", target, ChiSquared, Thresholds{MinCount: 4}, []Word{"a", "c", "b"}},
+		{"significant only", target, LogLikelihood, Thresholds{MinLogLikelihood: 1.55}, []Word{"a", "b", "e"}},
+		{"empty target", empty, LogLikelihood, Thresholds{}, []Word{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := Compare(d.target, reference, d.by, d.th)
+			words := make([]Word, len(got))
+			for i, k := range got {
+				words[i] = k.Word
+			}
+			if fmt.Sprint(words) != fmt.Sprint(d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, words)
+			}
+		})
+	}
+}
+
+func TestExtremes(t *testing.T) {
+	target, reference := NewCounter(), NewCounter()
+	target.Add("a a a a b c c d")
+	reference.Add("a b b b b c c e e")
+	overusedTarget, overusedReference := NewCounter(), NewCounter()
+	overusedTarget.Add("a a a b b c")
+	overusedReference.Add("a b c d e f g h")
+	tests := []struct {
+		name              string
+		result            []Keyness
+		k                 int
+		expectedOverused  []Word
+		expectedUnderused []Word
+	}{
+		{"both sides", Compare(target, reference, LogLikelihood, Thresholds{}), 1, []Word{"a"}, []Word{"e"}},
+		{"fewer than k on a side", Compare(target, reference, LogLikelihood, Thresholds{}), 3, []Word{"a", "d", "c"}, []Word{"e", "b"}},
+		{"all overused", Compare(overusedTarget, overusedReference, LogLikelihood, Thresholds{MinCount: 2}), 1, []Word{"a"}, []Word{}},
+		{"equal frequencies", Compare(target, target, LogLikelihood, Thresholds{}), 2, []Word{}, []Word{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			overused, underused := Extremes(d.result, d.k)
+			for _, v := range []struct {
+				got      []Keyness
+				expected []Word
+			}{{overused, d.expectedOverused}, {underused, d.expectedUnderused}} {
+				words := make([]Word, len(v.got))
+				for i, k := range v.got {
+					words[i] = k.Word
+				}
+				if fmt.Sprint(words) != fmt.Sprint(v.expected) {
+					t.Errorf("Expected %v, got %v", v.expected, words)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStatistic(t *testing.T) {
+	for _, s := range []Statistic{LogLikelihood, ChiSquared, LogRatio} {
+		if got, err := ParseStatistic(s.String()); got != s || err != nil {
+			t.Errorf("Expected %v, got %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseStatistic("p"); !errors.Is(err, ErrUnknownStatistic) {
+		t.Errorf("Expected %v, got %v", ErrUnknownStatistic, err)
+	}
+}
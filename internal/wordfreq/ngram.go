@@ -0,0 +1,72 @@
+package wordfreq
+
+import (
+	"slices"
+	"strings"
+)
+
+// NGramSeparator joins the words of a word n-gram: the bigram of "New" and "York" is "new york".
+const NGramSeparator = " "
+
+// wordNGrams returns the n-grams of words ending at the words from index from on,
+// the first n-1 words end no n-gram.
+func wordNGrams(words []Word, n, from int) []Word {
+	from = max(from, n-1)
+	if from >= len(words) {
+		return nil
+	}
+	result := make([]Word, 0, len(words)-from)
+	var b strings.Builder
+	for i := from; i < len(words); i++ {
+		b.Reset()
+		for j, w := range words[i-n+1 : i+1] {
+			if j > 0 {
+				b.WriteString(NGramSeparator)
+			}
+			b.WriteString(string(w))
+		}
+		result = append(result, Word(b.String()))
+	}
+	return result
+}
+
+// charNGrams returns the n-grams of runes of every word in turn,
+// words shorter than n runes have none.
+func charNGrams(words []Word, n int) []Word {
+	var result []Word
+	for _, w := range words {
+		runes := []rune(string(w))
+		for i := n; i <= len(runes); i++ {
+			result = append(result, Word(runes[i-n:i]))
+		}
+	}
+	return result
+}
+
+// lastWords returns at most n last words of words.
+func lastWords(words []Word, n int) []Word {
+	return words[max(len(words)-n, 0):]
+}
+
+// ngramStream makes word n-grams of words which come in parts: the last n-1 words
+// of a part begin the n-grams of the next one. Surface forms are joined alike.
+type ngramStream struct {
+	n               int
+	tail, formsTail []Word
+}
+
+// next returns the n-grams ending at words and the n-grams of their forms, nil if forms is nil.
+func (g *ngramStream) next(words, forms []Word) ([]Word, []Word) {
+	if g.n <= 1 {
+		return words, forms
+	}
+	all := slices.Concat(g.tail, words)
+	g.tail = slices.Clone(lastWords(all, g.n-1))
+	grams := wordNGrams(all, g.n, 0)
+	if forms == nil {
+		return grams, nil
+	}
+	allForms := slices.Concat(g.formsTail, forms)
+	g.formsTail = slices.Clone(lastWords(allForms, g.n-1))
+	return grams, wordNGrams(allForms, g.n, 0)
+}
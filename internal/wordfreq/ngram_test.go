@@ -0,0 +1,117 @@
+package wordfreq
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ExampleWithNGrams
+func ExampleWithNGrams() {
+	fmt.Printf("%q\n", GetResultWordsSlice("New York is big. I love New York!", 2, WithNGrams(2)))
+	// Output: ["new york" "big i"]
+}
+
+// ExampleWithCharNGrams
+func ExampleWithCharNGrams() {
+	fmt.Println(GetResultWordsSlice("banana bandana", 3, WithCharNGrams(2)))
+	// Output: [an na ba]
+}
+
+func TestGetResultWordsSlice_NGrams(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		k        int
+		opts     []Option
+		expected WordSlice
+	}{
+		{"bigrams", "a b a b c", 3, []Option{WithNGrams(2)}, WordSlice{"a b", "b a", "b c"}},
+		{"trigrams", "a b c a b c a b", 2, []Option{WithNGrams(3)}, WordSlice{"a b c", "b c a"}},
+		{"fewer words than n", "a b", 5, []Option{WithNGrams(3)}, WordSlice{}},
+		{"one is words", "b a b", 5, []Option{WithNGrams(1)}, WordSlice{"b", "a"}},
+		{"first occurrence", "x y z x", 3, []Option{WithNGrams(2), WithTieBreak(FirstOccurrence)},
+			WordSlice{"x y", "y z", "z x"}},
+		{"last occurrence", "x y z x", 3, []Option{WithNGrams(2), WithTieBreak(LastOccurrence)},
+			WordSlice{"z x", "y z", "x y"}},
+		{"stemmed", "Running dogs ran with the running dog", 1,
+			[]Option{WithNGrams(2), WithNormalizer(EnglishStemmer{})}, WordSlice{"run dog"}},
+		{"characters", "abab", 2, []Option{WithCharNGrams(2)}, WordSlice{"ab", "ba"}},
+		{"characters within words", "ab ab", 5, []Option{WithCharNGrams(2)}, WordSlice{"ab"}},
+		{"cyrillic characters", "дом дома", 5, []Option{WithCharNGrams(3)}, WordSlice{"дом", "ома"}},
+		{"short words left out", "a ab abc", 5, []Option{WithCharNGrams(3)}, WordSlice{"abc"}},
+		{"last setting wins", "a b a", 5, []Option{WithCharNGrams(2), WithNGrams(2)}, WordSlice{"a b", "b a"}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := GetResultWordsSlice(d.input, d.k, d.opts...); !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestCounter_NGramsAcrossParts(t *testing.T) {
+	whole := NewCounter(WithNGrams(3))
+	whole.Add("one two three four five")
+	parts := NewCounter(WithNGrams(3))
+	for _, s := range []string{"one", "two", "three four", "", "five"} {
+		parts.Add(s)
+	}
+	if !maps.Equal(parts.stats, whole.stats) || parts.Total() != 3 {
+		t.Errorf("Expected %v, got %v", whole.stats, parts.stats)
+	}
+}
+
+func TestCounter_NGramsParallel(t *testing.T) {
+	text := corpus(20000)
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"bigrams", []Option{WithNGrams(2)}},
+		{"trigrams stemmed", []Option{WithNGrams(3), WithNormalizer(EnglishStemmer{})}},
+		{"characters", []Option{WithCharNGrams(3)}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			for _, chunk := range []int{8, 1 << 10} {
+				sequential := NewCounter(d.opts...)
+				sequential.Add("before")
+				sequential.ReadFrom(strings.NewReader(text))
+				sequential.Add("after Word1")
+				c := NewCounter(append(d.opts, WithWorkers(4))...)
+				c.chunk = chunk
+				c.Add("before")
+				c.ReadFrom(strings.NewReader(text))
+				c.Add("after Word1")
+				if c.Total() != sequential.Total() {
+					t.Errorf("Expected %d n-grams, got %d", sequential.Total(), c.Total())
+				}
+				if !maps.Equal(c.stats, sequential.stats) {
+					t.Errorf("chunk %d: Expected the same counts and positions as counted sequentially", chunk)
+				}
+				for w, forms := range sequential.forms {
+					if !maps.Equal(c.Forms(w), forms) {
+						t.Errorf("%q: Expected %v, got %v", w, forms, c.Forms(w))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSpaceSaving_NGrams(t *testing.T) {
+	s := NewSpaceSaving(10, WithNGrams(2))
+	s.Add("a b")
+	s.Add("a b")
+	top, guaranteed := s.Top(2)
+	expected := []Estimate{{"a b", 2, 0}, {"b a", 1, 0}}
+	if !slices.Equal(top, expected) || !guaranteed || s.Total() != 3 {
+		t.Errorf("Expected %v, got %v %v %d", expected, top, guaranteed, s.Total())
+	}
+}
@@ -0,0 +1,20 @@
+package wordfreq
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// nfc returns s in Unicode Normalization Form C: "e" followed by U+0301
+// COMBINING ACUTE ACCENT becomes "é", and the marks of a letter are composed
+// in canonical order whichever order they were written in.
+func nfc(s string) string {
+	return norm.NFC.String(s)
+}
+
+// foldRune returns the case folded form of r: upper and title case letters
+// and case variants like the final sigma map to the same lower case letter.
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
@@ -0,0 +1,55 @@
+package wordfreq
+
+import "testing"
+
+func Test_nfc(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ascii", "hello", "hello"},
+		{"composed", "caf\u00e9", "caf\u00e9"},
+		{"decomposed", "cafe\u0301", "caf\u00e9"},
+		{"cyrillic short i", "\u0438\u0306", "\u0439"},
+		{"two marks", "e\u0323\u0302", "\u1ec7"},
+		{"two marks reordered", "e\u0302\u0323", "\u1ec7"},
+		{"precomposed and mark", "\u00ea\u0323", "\u1ec7"},
+		{"no composition", "q\u0301", "q\u0301"},
+		{"blocked", "a\u0328\u0328\u0301", "\u0105\u0328\u0301"},
+		{"hangul", "\u1100\u1161\u11a8", "\uac01"},
+		{"singleton", "\u212b", "\u00c5"},
+		{"composition exclusion", "\u0958", "\u0915\u093c"},
+		{"other scripts", "\u0627\u0653", "\u0622"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := nfc(d.input); got != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func Test_foldRune(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    rune
+		expected rune
+	}{
+		{"latin", 'A', 'a'},
+		{"cyrillic", 'Ё', 'ё'},
+		{"final sigma", 'ς', 'σ'},
+		{"long s", 'ſ', 's'},
+		{"digit", '7', '7'},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := foldRune(d.input); got != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package wordfreq
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Normalizer maps a word to the form it is counted under, e.g. its stem,
+// so that "running" and "runs" are counted together as "run".
+type Normalizer interface {
+	Normalize(w Word) Word
+}
+
+// NormalizerFunc is an adapter to use an ordinary function as a Normalizer.
+type NormalizerFunc func(w Word) Word
+
+// Normalize calls f(w).
+func (f NormalizerFunc) Normalize(w Word) Word {
+	return f(w)
+}
+
+// AutoStemmer stems every word by the stemmer of its script:
+// Cyrillic words by RussianStemmer and other words by EnglishStemmer.
+// It suits bilingual English and Russian text.
+type AutoStemmer struct{}
+
+// Normalize returns the stem of w.
+func (AutoStemmer) Normalize(w Word) Word {
+	for _, r := range w {
+		if unicode.IsLetter(r) {
+			if unicode.Is(unicode.Cyrillic, r) {
+				return RussianStemmer{}.Normalize(w)
+			}
+			break
+		}
+	}
+	return EnglishStemmer{}.Normalize(w)
+}
+
+// NewStemmer returns the stemmer for a language: "en", "ru"
+// or "auto" for a text in both of them.
+func NewStemmer(lang string) (Normalizer, error) {
+	switch strings.ToLower(strings.TrimSpace(lang)) {
+	case "en":
+		return EnglishStemmer{}, nil
+	case "ru":
+		return RussianStemmer{}, nil
+	case "auto":
+		return AutoStemmer{}, nil
+	}
+	return nil, fmt.Errorf("%w: %q, use en, ru or auto", ErrUnknownLanguage, lang)
+}
@@ -0,0 +1,99 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// ExampleWithNormalizer
+func ExampleWithNormalizer() {
+	c := NewCounter(WithNormalizer(EnglishStemmer{}))
+	c.Add("He runs, she is running, they ran. The runner runs")
+	top := c.Top(1)
+	fmt.Println(top, c.Forms(top[0]))
+	// Output: [run] map[ran:1 running:1 runs:2]
+}
+
+func TestNewStemmer(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		word     Word
+		expected Word
+		err      error
+	}{
+		{"english", "en", "books", "book", nil},
+		{"russian", " RU ", "книги", "книг", nil},
+		{"auto english", "auto", "books", "book", nil},
+		{"auto russian", "auto", "книги", "книг", nil},
+		{"unknown", "de", "", "", ErrUnknownLanguage},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			n, err := NewStemmer(d.lang)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := n.Normalize(d.word); got != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestCounter_Forms(t *testing.T) {
+	en, _ := LoadStopWords("en")
+	text := "The Books and the book, книги и книга. Books!"
+	tests := []struct {
+		name   string
+		opts   []Option
+		counts map[Word]int
+		forms  map[Word]int
+	}{
+		{"without normalizer", nil,
+			map[Word]int{"the": 2, "books": 2, "and": 1, "book": 1, "книги": 1, "и": 1, "книга": 1}, nil},
+		{"auto stemmer", []Option{WithNormalizer(AutoStemmer{})},
+			map[Word]int{"the": 2, "book": 3, "and": 1, "книг": 2, "и": 1}, map[Word]int{"books": 2, "book": 1}},
+		{"stop words before stemming", []Option{WithNormalizer(AutoStemmer{}), WithStopWords(en)},
+			map[Word]int{"book": 3, "книг": 2, "и": 1}, map[Word]int{"books": 2, "book": 1}},
+		{"function", []Option{WithNormalizer(NormalizerFunc(func(w Word) Word { return Word(strings.TrimSuffix(string(w), "s")) }))},
+			map[Word]int{"the": 2, "book": 3, "and": 1, "книги": 1, "и": 1, "книга": 1}, map[Word]int{"books": 2, "book": 1}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewCounter(d.opts...)
+			c.Add(text)
+			if got := c.Counts(); !maps.Equal(got, d.counts) {
+				t.Errorf("Expected %v, got %v", d.counts, got)
+			}
+			if got := c.Forms("book"); !maps.Equal(got, d.forms) {
+				t.Errorf("Expected %v, got %v", d.forms, got)
+			}
+		})
+	}
+}
+
+func TestCounter_FormsParallel(t *testing.T) {
+	text := strings.Repeat("runs running ran run, книги книгой книга.\n", 500)
+	sequential := NewCounter(WithNormalizer(AutoStemmer{}))
+	sequential.ReadFrom(strings.NewReader(text))
+	c := NewCounter(WithNormalizer(AutoStemmer{}), WithWorkers(4))
+	c.chunk = 256
+	c.ReadFrom(strings.NewReader(text))
+	if !maps.Equal(c.stats, sequential.stats) {
+		t.Errorf("Expected the same counts and positions as counted sequentially")
+	}
+	for _, w := range []Word{"run", "книг"} {
+		if got, expected := c.Forms(w), sequential.Forms(w); !maps.Equal(got, expected) {
+			t.Errorf("%q: Expected %v, got %v", w, expected, got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package wordfreq
+
+import "runtime"
+
+// Option configures GetWordsMap and GetResultWordsSlice.
+type Option func(*options)
+
+// options holds the settings of counting and ranking.
+type options struct {
+	tieBreak  TieBreak
+	tokenizer Tokenizer
+	workers   int
+	stopWords StopWords
+	filters   []Filter
+	normalize Normalizer
+	ngrams    int  // number of words or characters in a counted n-gram
+	chars     bool // whether n-grams are of characters rather than words
+}
+
+// WithTieBreak sets the order of words with equal frequency.
+func WithTieBreak(t TieBreak) Option {
+	return func(o *options) {
+		o.tieBreak = t
+	}
+}
+
+// WithTokenizer sets how text is split into words, UnicodeTokenizer by default.
+func WithTokenizer(t Tokenizer) Option {
+	return func(o *options) {
+		o.tokenizer = t
+	}
+}
+
+// WithWorkers sets the number of goroutines counting words, one by default.
+// Zero or a negative n uses runtime.GOMAXPROCS(0) goroutines.
+// The tokenizer must be safe for concurrent use when n is not 1.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.workers = n
+	}
+}
+
+// WithStopWords leaves the words of s out of counting.
+// Several lists may be combined with StopWords.Merge.
+func WithStopWords(s StopWords) Option {
+	return func(o *options) {
+		o.stopWords = s
+	}
+}
+
+// WithFilters counts only the words every filter keeps. Filters are applied
+// in order after the tokenizer and stop words and before the normalizer,
+// so they see words as the tokenizer returns them. Filters of several
+// WithFilters options are combined.
+func WithFilters(filters ...Filter) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+// WithNormalizer counts words under their normalized forms, e.g. stems.
+// Counter reports the forms merged into every counted word, see Counter.Forms.
+func WithNormalizer(n Normalizer) Option {
+	return func(o *options) {
+		o.normalize = n
+	}
+}
+
+// WithNGrams counts word n-grams, sequences of n adjacent words joined
+// by NGramSeparator, instead of single words. Stop words are left out
+// before n-grams are made. An n less than 2 counts single words.
+func WithNGrams(n int) Option {
+	return func(o *options) {
+		o.ngrams, o.chars = max(n, 1), false
+	}
+}
+
+// WithCharNGrams counts character n-grams, sequences of n adjacent characters
+// of every word, instead of words. Words shorter than n characters are left out.
+// Forms merged by a normalizer are not kept for character n-grams.
+func WithCharNGrams(n int) Option {
+	return func(o *options) {
+		o.ngrams, o.chars = max(n, 1), true
+	}
+}
+
+// newOptions applies opts to the default settings.
+func newOptions(opts []Option) options {
+	o := options{tokenizer: UnicodeTokenizer{}, workers: 1, ngrams: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// tokenizeForms splits s into words to count: stop words and words the filters
+// do not keep are left out and the rest are normalized. If there is a normalizer,
+// it also returns the words as they were written in s, nil otherwise.
+// With character n-grams the n-grams of the words are returned instead.
+// Word n-grams span the parts of a text, they are made by ngramStream.
+func (o options) tokenizeForms(s string) (words, forms []Word) {
+	_, normalized := o.tokenizer.(UnicodeTokenizer)
+	forms = filter(o.stopWords.filter(o.tokenizer.Tokenize(s), normalized), o.filters)
+	if o.normalize == nil {
+		words, forms = forms, nil
+	} else {
+		words = make([]Word, len(forms))
+		for i, w := range forms {
+			words[i] = o.normalize.Normalize(w)
+		}
+	}
+	if o.chars {
+		return charNGrams(words, o.ngrams), nil
+	}
+	return words, forms
+}
+
+// ngramWords returns the number of words in a counted word n-gram, 1 for single words.
+func (o options) ngramWords() int {
+	if o.chars {
+		return 1
+	}
+	return o.ngrams
+}
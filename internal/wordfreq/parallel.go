@@ -0,0 +1,225 @@
+package wordfreq
+
+import (
+	"hash/maphash"
+	"io"
+	"slices"
+	"sync"
+)
+
+// chunk is a part of the text cut at whitespace, seq is its ordinal number.
+type chunk struct {
+	seq  int
+	text string
+}
+
+// position is the place of a word in the text: a chunk and the index of the word in it.
+// The position of a word n-gram is that of its last word.
+type position struct {
+	chunk, index int
+}
+
+// before reports whether p precedes q in the text.
+func (p position) before(q position) bool {
+	return p.chunk < q.chunk || p.chunk == q.chunk && p.index < q.index
+}
+
+// chunkStat is what a worker knows about a word: positions are local to chunks
+// because the number of words in the chunks before is not known yet.
+type chunkStat struct {
+	count       int
+	first, last position
+}
+
+// shard is the part of the words with the same hash: their stats and,
+// with a normalizer, the forms merged into them.
+type shard struct {
+	stats map[Word]chunkStat
+	forms map[Word]map[Word]int
+}
+
+// edge holds the first and the last n-1 words of a chunk with their forms:
+// they make the word n-grams spanning chunks.
+type edge struct {
+	head, headForms []Word
+	tail, tailForms []Word
+}
+
+// newEdge returns the edge of the words of a chunk and their forms, nil without a normalizer.
+func newEdge(words, forms []Word, n int) edge {
+	e := edge{head: slices.Clone(words[:min(n-1, len(words))]), tail: slices.Clone(lastWords(words, n-1))}
+	if forms != nil {
+		e.headForms, e.tailForms = slices.Clone(forms[:len(e.head)]), slices.Clone(lastWords(forms, n-1))
+	}
+	return e
+}
+
+// partial is what a worker has counted: stats of its words sharded by hash,
+// the number of words in every chunk it has processed and, for word n-grams,
+// the edges of the chunks.
+type partial struct {
+	shards []shard
+	sizes  map[int]int
+	edges  map[int]edge
+}
+
+// readParallel counts the words of r like ReadFrom does with c.opts.workers goroutines.
+//
+// The calling goroutine cuts the text into chunks, workers count them into
+// local maps sharded by word hash, then every shard is merged by its own goroutine.
+// Word positions become global once the numbers of words of all chunks are known.
+// Word n-grams spanning chunks are made from the edges of the chunks at the end.
+func (c *Counter) readParallel(r io.Reader) (int64, error) {
+	workers, grams := c.opts.workers, c.grams.n
+	seed := maphash.MakeSeed()
+	chunks := make(chan chunk, workers)
+	partials := make([]partial, workers)
+	var wg sync.WaitGroup
+	for i := range partials {
+		partials[i] = partial{shards: newShards(workers), sizes: map[int]int{}, edges: map[int]edge{}}
+		wg.Add(1)
+		go func(p partial) {
+			defer wg.Done()
+			for ch := range chunks {
+				words, forms := c.opts.tokenizeForms(ch.text)
+				p.sizes[ch.seq] = len(words)
+				if grams > 1 {
+					p.edges[ch.seq] = newEdge(words, forms, grams)
+					words, forms = wordNGrams(words, grams, 0), wordNGrams(forms, grams, 0)
+				}
+				countChunk(p.shards, seed, words, forms, ch.seq, grams-1)
+			}
+		}(partials[i])
+	}
+	seq := 0
+	n, err := readChunks(r, c.chunk, func(text string) {
+		chunks <- chunk{seq, text}
+		seq++
+	})
+	close(chunks)
+	wg.Wait()
+
+	sizes := make([]int, seq)
+	edges := make([]edge, seq)
+	for _, p := range partials {
+		for i, size := range p.sizes {
+			sizes[i] = size
+		}
+		for i, e := range p.edges {
+			edges[i] = e
+		}
+	}
+	// the words read so far, the first grams-1 of them end no n-gram
+	read := c.words + len(c.grams.tail)
+	offsets := make([]int, seq) // number of words before every chunk
+	for i, size := range sizes {
+		offsets[i] = read - (grams - 1)
+		read += size
+	}
+	if grams > 1 {
+		partials = append(partials, c.countEdges(edges, seed, workers))
+	}
+	c.words = max(read-(grams-1), 0)
+	merged := mergeShards(partials)
+	for _, sh := range merged {
+		for w, st := range sh.stats {
+			first, last := offsets[st.first.chunk]+st.first.index, offsets[st.last.chunk]+st.last.index
+			if old, ok := c.stats[w]; ok {
+				first = old.first
+				st.count += old.count
+			}
+			c.stats[w] = wordStat{count: st.count, first: first, last: last}
+		}
+		for w, forms := range sh.forms {
+			for f, n := range forms {
+				addForm(c.forms, w, f, n)
+			}
+		}
+	}
+	return n, err
+}
+
+// countEdges counts the word n-grams spanning chunks into a partial of their own
+// and leaves the last words of the chunks in c.grams for the text to follow.
+func (c *Counter) countEdges(edges []edge, seed maphash.Seed, shards int) partial {
+	p := partial{shards: newShards(shards)}
+	for seq, e := range edges {
+		// the n-grams end at the first words of the chunk, the first ones
+		// at the beginning of the text may lack preceding words
+		first := c.grams.n - 1 - len(c.grams.tail)
+		words, forms := c.grams.next(e.head, e.headForms)
+		countChunk(p.shards, seed, words, forms, seq, first)
+		if len(e.tail) == c.grams.n-1 {
+			c.grams.tail, c.grams.formsTail = e.tail, e.tailForms
+		}
+	}
+	return p
+}
+
+// newShards returns n empty shards.
+func newShards(n int) []shard {
+	shards := make([]shard, n)
+	for i := range shards {
+		shards[i] = shard{stats: map[Word]chunkStat{}, forms: map[Word]map[Word]int{}}
+	}
+	return shards
+}
+
+// countChunk counts the words of the chunk seq into shards, the first of them
+// at index first of the chunk. forms are the words as written in the text,
+// nil without a normalizer.
+func countChunk(shards []shard, seed maphash.Seed, words, forms []Word, seq, first int) {
+	for i, w := range words {
+		sh := shards[maphash.String(seed, string(w))%uint64(len(shards))]
+		pos := position{seq, first + i}
+		st, ok := sh.stats[w]
+		if !ok {
+			st.first = pos
+		}
+		st.count++
+		st.last = pos
+		sh.stats[w] = st
+		if forms != nil {
+			addForm(sh.forms, w, forms[i], 1)
+		}
+	}
+}
+
+// mergeShards merges the shards of all partials, shard i of every partial
+// holds the same words, so every shard is merged by its own goroutine.
+func mergeShards(partials []partial) []shard {
+	merged := make([]shard, len(partials[0].shards))
+	var wg sync.WaitGroup
+	for i := range merged {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := partials[0].shards[i]
+			for _, p := range partials[1:] {
+				for w, forms := range p.shards[i].forms {
+					for f, n := range forms {
+						addForm(result.forms, w, f, n)
+					}
+				}
+				for w, st := range p.shards[i].stats {
+					old, ok := result.stats[w]
+					if !ok {
+						result.stats[w] = st
+						continue
+					}
+					old.count += st.count
+					if st.first.before(old.first) {
+						old.first = st.first
+					}
+					if old.last.before(st.last) {
+						old.last = st.last
+					}
+					result.stats[w] = old
+				}
+			}
+			merged[i] = result
+		}(i)
+	}
+	wg.Wait()
+	return merged
+}
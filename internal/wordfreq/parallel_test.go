@@ -0,0 +1,101 @@
+package wordfreq
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// corpus returns a text of n words drawn from a vocabulary with
+// a few frequent and many rare words, like natural language.
+func corpus(n int) string {
+	r := rand.New(rand.NewSource(1))
+	vocabulary := make([]string, 5000)
+	for i := range vocabulary {
+		vocabulary[i] = fmt.Sprintf("Word%d", i)
+	}
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(len(vocabulary)-1))
+	var b strings.Builder
+	for i := range n {
+		b.WriteString(vocabulary[zipf.Uint64()])
+		if i%12 == 11 {
+			b.WriteString(".\n")
+		} else {
+			b.WriteString(", ")
+		}
+	}
+	return b.String()
+}
+
+func TestCounter_ReadFromParallel(t *testing.T) {
+	text := corpus(20000)
+	sequential := NewCounter()
+	sequential.chunk = 1 << 10
+	sequential.Add("before")
+	sequential.ReadFrom(strings.NewReader(text))
+	sequential.Add("after Word1")
+	tests := []struct {
+		name    string
+		workers int
+		chunk   int
+	}{
+		{"two workers", 2, 1 << 10},
+		{"eight workers", 8, 1 << 10},
+		{"more workers than chunks", 16, DefaultChunkSize},
+		{"all cpus", 0, 4 << 10},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewCounter(WithWorkers(d.workers))
+			c.chunk = d.chunk
+			c.Add("before")
+			c.ReadFrom(strings.NewReader(text))
+			c.Add("after Word1")
+			if c.Total() != sequential.Total() {
+				t.Errorf("Expected %d words, got %d", sequential.Total(), c.Total())
+			}
+			if !maps.Equal(c.stats, sequential.stats) {
+				t.Errorf("Expected the same counts and positions as counted sequentially")
+			}
+		})
+	}
+}
+
+func TestGetResultWordsSlice_Workers(t *testing.T) {
+	text := corpus(10000)
+	expected := GetResultWordsSlice(text, 20, WithTieBreak(LastOccurrence))
+	got := GetResultWordsSlice(text, 20, WithTieBreak(LastOccurrence), WithWorkers(4))
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestCounter_ReadFromParallelEmpty(t *testing.T) {
+	c := NewCounter(WithWorkers(4))
+	if n, err := c.ReadFrom(strings.NewReader("")); n != 0 || err != nil || c.Total() != 0 {
+		t.Errorf("Expected nothing counted, got %d bytes, %d words, %v", n, c.Total(), err)
+	}
+}
+
+func BenchmarkGetWordsMap(b *testing.B) {
+	text := corpus(1 << 20)
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		GetWordsMap(text)
+	}
+}
+
+func BenchmarkCounter_ReadFrom(b *testing.B) {
+	text := corpus(1 << 20)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for b.Loop() {
+				NewCounter(WithWorkers(workers)).ReadFrom(strings.NewReader(text))
+			}
+		})
+	}
+}
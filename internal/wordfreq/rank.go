@@ -0,0 +1,160 @@
+package wordfreq
+
+import (
+	"cmp"
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnknownTieBreak is returned by ParseTieBreak for unsupported names.
+var ErrUnknownTieBreak = errors.New("unknown tie-breaking rule")
+
+// TieBreak decides the order of words with equal frequency.
+type TieBreak int
+
+// Tie-breaking rules, Lexicographic is the default.
+const (
+	Lexicographic   TieBreak = iota // words in byte-wise lexicographic order
+	FirstOccurrence                 // the word which appeared earlier in the text first
+	LastOccurrence                  // the word which appeared later in the text first, the most recent one
+	Length                          // shorter words first, words of the same length lexicographically
+)
+
+// tieBreakNames - names of tie-breaking rules for the command line
+var tieBreakNames = map[TieBreak]string{
+	Lexicographic:   "lex",
+	FirstOccurrence: "first",
+	LastOccurrence:  "last",
+	Length:          "length",
+}
+
+// String returns the name of the rule accepted by ParseTieBreak.
+func (t TieBreak) String() string {
+	if name, ok := tieBreakNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TieBreak(%d)", int(t))
+}
+
+// ParseTieBreak returns the rule with the given name: lex, first, last or length.
+func ParseTieBreak(name string) (TieBreak, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for t, n := range tieBreakNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q, use lex, first, last or length", ErrUnknownTieBreak, name)
+}
+
+// wordStat is what is known about a word of the text:
+// the number of occurrences and word positions of the first and the last one.
+type wordStat struct {
+	count       int
+	first, last int
+}
+
+// ranked is a word with its stat, the element of rankings.
+type ranked struct {
+	word Word
+	stat wordStat
+}
+
+// rankOrder returns the ranking comparison: a < b when a is more frequent,
+// words with equal frequency are ordered by t. The order is total:
+// when t does not tell two words apart they are ordered lexicographically.
+func rankOrder(t TieBreak) func(a, b ranked) int {
+	return func(a, b ranked) int {
+		if c := cmp.Compare(b.stat.count, a.stat.count); c != 0 {
+			return c
+		}
+		var c int
+		switch t {
+		case FirstOccurrence:
+			c = cmp.Compare(a.stat.first, b.stat.first)
+		case LastOccurrence:
+			c = cmp.Compare(b.stat.last, a.stat.last)
+		case Length:
+			c = cmp.Compare(utf8.RuneCountInString(string(a.word)), utf8.RuneCountInString(string(b.word)))
+		}
+		if c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.word), string(b.word))
+	}
+}
+
+// rankWords returns the words sorted in descending order of frequency,
+// words with equal frequency are ordered by t, see rankOrder.
+func rankWords(stats map[Word]wordStat, t TieBreak) WordSlice {
+	return words(rankAll(stats, t))
+}
+
+// rankAll returns the words with their stats in the order of rankWords.
+func rankAll(stats map[Word]wordStat, t TieBreak) []ranked {
+	all := make([]ranked, 0, len(stats))
+	for w, st := range stats {
+		all = append(all, ranked{w, st})
+	}
+	slices.SortFunc(all, rankOrder(t))
+	return all
+}
+
+// topWords returns the k most frequent words in the order of rankWords
+// in O(n log k) time keeping the best k words seen so far in a min-heap.
+func topWords(stats map[Word]wordStat, t TieBreak, k int) WordSlice {
+	return words(topRanked(stats, t, k))
+}
+
+// topRanked returns the k most frequent words with their stats in the order of topWords.
+func topRanked(stats map[Word]wordStat, t TieBreak, k int) []ranked {
+	if k <= 0 {
+		return []ranked{}
+	}
+	h := &rankHeap{less: rankOrder(t)}
+	for w, st := range stats {
+		r := ranked{w, st}
+		switch {
+		case len(h.items) < k:
+			heap.Push(h, r)
+		case h.less(r, h.items[0]) < 0:
+			h.items[0] = r
+			heap.Fix(h, 0)
+		}
+	}
+	result := make([]ranked, len(h.items))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(ranked)
+	}
+	return result
+}
+
+// words returns the words of a ranking.
+func words(rs []ranked) WordSlice {
+	result := make(WordSlice, len(rs))
+	for i, r := range rs {
+		result[i] = r.word
+	}
+	return result
+}
+
+// rankHeap is a heap.Interface with the worst ranked word at the root.
+type rankHeap struct {
+	items []ranked
+	less  func(a, b ranked) int
+}
+
+func (h *rankHeap) Len() int           { return len(h.items) }
+func (h *rankHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) > 0 }
+func (h *rankHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *rankHeap) Push(x any)         { h.items = append(h.items, x.(ranked)) }
+
+func (h *rankHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
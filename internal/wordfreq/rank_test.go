@@ -0,0 +1,126 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// ExampleWithTieBreak
+func ExampleWithTieBreak() {
+	words := GetResultWordsSlice("dd c bbb c dd a", 3, WithTieBreak(FirstOccurrence))
+	fmt.Println(words)
+	// Output: [dd c bbb]
+}
+
+func TestGetResultWordsSlice_TieBreak(t *testing.T) {
+	const text = "pear fig apple fig kiwi pear plum kiwi"
+	tests := []struct {
+		name     string
+		tieBreak TieBreak
+		expected WordSlice
+	}{
+		{"lexicographic", Lexicographic, WordSlice{"fig", "kiwi", "pear", "apple", "plum"}},
+		{"first occurrence", FirstOccurrence, WordSlice{"pear", "fig", "kiwi", "apple", "plum"}},
+		{"last occurrence", LastOccurrence, WordSlice{"kiwi", "pear", "fig", "plum", "apple"}},
+		{"length", Length, WordSlice{"fig", "kiwi", "pear", "plum", "apple"}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := GetResultWordsSlice(text, 10, WithTieBreak(d.tieBreak))
+			if !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetResultWordsSlice_Deterministic(t *testing.T) {
+	const text = "a b c d e f g h i j k l m n o p"
+	expected := GetResultWordsSlice(text, 5)
+	for range 20 {
+		if got := GetResultWordsSlice(text, 5); !slices.Equal(got, expected) {
+			t.Fatalf("Expected %v, got %v", expected, got)
+		}
+	}
+	if !slices.Equal(expected, WordSlice{"a", "b", "c", "d", "e"}) {
+		t.Errorf("Expected lexicographic order, got %v", expected)
+	}
+}
+
+func TestParseTieBreak(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected TieBreak
+		err      error
+	}{
+		{"lex", "lex", Lexicographic, nil},
+		{"first", "first", FirstOccurrence, nil},
+		{"last upper case", " LAST ", LastOccurrence, nil},
+		{"length", "length", Length, nil},
+		{"unknown", "random", 0, ErrUnknownTieBreak},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := ParseTieBreak(d.input)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+			if back, _ := ParseTieBreak(got.String()); d.err == nil && back != got {
+				t.Errorf("Expected %v, got %v", got, back)
+			}
+		})
+	}
+}
+
+func Test_topWords(t *testing.T) {
+	c := NewCounter()
+	c.Add(corpus(5000))
+	for _, tieBreak := range []TieBreak{Lexicographic, FirstOccurrence, LastOccurrence, Length} {
+		sorted := rankWords(c.stats, tieBreak)
+		for _, k := range []int{1, 2, 10, 100, len(sorted) - 1, len(sorted)} {
+			t.Run(fmt.Sprintf("%v/%d", tieBreak, k), func(t *testing.T) {
+				if got := topWords(c.stats, tieBreak, k); !slices.Equal(got, sorted[:k]) {
+					t.Errorf("Expected %v, got %v", sorted[:k], got)
+				}
+			})
+		}
+	}
+	if got := topWords(c.stats, Lexicographic, 0); len(got) != 0 {
+		t.Errorf("Expected no words, got %v", got)
+	}
+}
+
+// vocabulary returns stats of n distinct words with counts of a natural language text.
+func vocabulary(n int) map[Word]wordStat {
+	r := rand.New(rand.NewSource(1))
+	stats := make(map[Word]wordStat, n)
+	for i := range n {
+		stats[Word(fmt.Sprintf("w%d", i))] = wordStat{count: 1 + int(1e6/float64(1+r.Intn(n))), first: i, last: i}
+	}
+	return stats
+}
+
+func BenchmarkTop(b *testing.B) {
+	for _, n := range []int{10000, 1000000} {
+		stats := vocabulary(n)
+		b.Run(fmt.Sprintf("heap/n=%d/k=10", n), func(b *testing.B) {
+			for b.Loop() {
+				topWords(stats, Lexicographic, 10)
+			}
+		})
+		b.Run(fmt.Sprintf("sort/n=%d/k=10", n), func(b *testing.B) {
+			for b.Loop() {
+				_ = rankWords(stats, Lexicographic)[:10]
+			}
+		})
+	}
+}
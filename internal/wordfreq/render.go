@@ -0,0 +1,164 @@
+package wordfreq
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLabelWidth is the width words are cut to in bar charts.
+const maxLabelWidth = 24
+
+// minBarWidth is the least width of the bars of a chart, however narrow the terminal.
+const minBarWidth = 10
+
+// barEighths - block characters of 1/8 to 8/8 of the width of a character
+var barEighths = []rune("▏▎▍▌▋▊▉█")
+
+// WriteBars writes the entries as a horizontal bar chart fitting lines of width
+// characters: a line per word with a bar of a length proportional to its count,
+// the count and the frequency in percent. The bar of the most frequent word
+// takes all the width left.
+//
+//	cc  ████████████████████ 4 36.36%
+//	aa  ███████████████      3 27.27%
+func (e EntrySlice) WriteBars(w io.Writer, width int) error {
+	if len(e) == 0 {
+		return nil
+	}
+	labels := make([]string, len(e))
+	suffixes := make([]string, len(e))
+	labelWidth, suffixWidth, maxCount := 0, 0, 0
+	for i, entry := range e {
+		labels[i] = truncate(string(entry.Word), maxLabelWidth)
+		suffixes[i] = fmt.Sprintf("%d %.2f%%", entry.Count, entry.Freq*100)
+		labelWidth = max(labelWidth, utf8.RuneCountInString(labels[i]))
+		suffixWidth = max(suffixWidth, len(suffixes[i]))
+		maxCount = max(maxCount, entry.Count)
+	}
+	barWidth := max(width-labelWidth-suffixWidth-3, minBarWidth)
+	var b bytes.Buffer
+	for i, entry := range e {
+		line := bar(entry.Count, maxCount, barWidth)
+		fmt.Fprintf(&b, "%s%s  %s%s %*s\n",
+			labels[i], strings.Repeat(" ", labelWidth-utf8.RuneCountInString(labels[i])),
+			line, strings.Repeat(" ", barWidth-utf8.RuneCountInString(line)),
+			suffixWidth, suffixes[i])
+	}
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// bar returns a bar of count out of maxCount characters of width, in eighths of a character.
+func bar(count, maxCount, width int) string {
+	if maxCount == 0 {
+		return ""
+	}
+	eighths := int(math.Round(float64(count) / float64(maxCount) * float64(width*8)))
+	if eighths == 0 && count > 0 {
+		eighths = 1
+	}
+	s := strings.Repeat(string(barEighths[7]), eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		s += string(barEighths[rest-1])
+	}
+	return s
+}
+
+// truncate cuts s to n characters, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// SVG word clouds
+const (
+	minFontSize = 12.0
+	maxFontSize = 64.0
+	// charWidth is the width of a character in font sizes, an estimate for a sans-serif font
+	charWidth = 0.6
+)
+
+// cloudColors - colors of words of a cloud, in the order of ranks
+var cloudColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// rect is a box of a word of a cloud.
+type rect struct {
+	x, y, w, h float64
+}
+
+// overlaps reports whether r and s intersect.
+func (r rect) overlaps(s rect) bool {
+	return r.x < s.x+s.w && s.x < r.x+r.w && r.y < s.y+s.h && s.y < r.y+r.h
+}
+
+// WriteSVG writes the entries as an SVG word cloud of width by height pixels.
+// Font sizes grow with the square root of counts, the most frequent word is
+// put in the middle and the others around it along a spiral. Words which
+// do not fit are left out. The layout is deterministic: the same entries
+// give the same picture. Every word has a tooltip with its count and frequency.
+func (e EntrySlice) WriteSVG(w io.Writer, width, height int) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	minCount, maxCount := math.MaxInt, 0
+	for _, entry := range e {
+		minCount, maxCount = min(minCount, entry.Count), max(maxCount, entry.Count)
+	}
+	canvas := rect{0, 0, float64(width), float64(height)}
+	var placed []rect
+	for i, entry := range e {
+		size := maxFontSize
+		if maxCount > minCount {
+			scale := math.Sqrt(float64(entry.Count-minCount) / float64(maxCount-minCount))
+			size = minFontSize + (maxFontSize-minFontSize)*scale
+		}
+		box, ok := place(placed, canvas, size*charWidth*float64(utf8.RuneCountInString(string(entry.Word))), size)
+		if !ok {
+			continue
+		}
+		placed = append(placed, box)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.1f" fill="%s"><title>`,
+			box.x, box.y+size*0.8, size, cloudColors[i%len(cloudColors)])
+		xml.EscapeText(&b, []byte(fmt.Sprintf("%s: %d, %.2f%%", entry.Word, entry.Count, entry.Freq*100)))
+		b.WriteString("</title>")
+		xml.EscapeText(&b, []byte(entry.Word))
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// place finds a place for a box of w by h in the canvas along an Archimedean spiral
+// from its center, where the box does not overlap the boxes placed before.
+func place(placed []rect, canvas rect, w, h float64) (rect, bool) {
+	cx, cy := canvas.w/2, canvas.h/2
+	maxRadius := math.Hypot(cx, cy)
+	for t := 0.0; ; t += 0.1 {
+		r := 2 * t
+		if r > maxRadius {
+			return rect{}, false
+		}
+		box := rect{cx + r*math.Cos(t) - w/2, cy + r*math.Sin(t) - h/2, w, h}
+		if box.x < 0 || box.y < 0 || box.x+box.w > canvas.w || box.y+box.h > canvas.h {
+			continue
+		}
+		free := true
+		for _, p := range placed {
+			if box.overlaps(p) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return box, true
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package wordfreq
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// ExampleEntrySlice_WriteBars
+func ExampleEntrySlice_WriteBars() {
+	GetResultEntries("aa bb cc aa cc cc cc aa ab ac bb", 3).WriteBars(os.Stdout, 40)
+	// Output:
+	// cc  ███████████████████████████ 4 36.36%
+	// aa  ████████████████████▎       3 27.27%
+	// bb  █████████████▌              2 18.18%
+}
+
+func Test_bar(t *testing.T) {
+	tests := []struct {
+		name              string
+		count, max, width int
+		expected          string
+	}{
+		{"full", 4, 4, 5, "█████"},
+		{"half", 2, 4, 5, "██▌"},
+		{"eighth", 1, 40, 1, "▏"},
+		{"tiny still shown", 1, 1000, 10, "▏"},
+		{"zero", 0, 4, 5, ""},
+		{"nothing counted", 0, 0, 5, ""},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := bar(d.count, d.max, d.width); got != d.expected {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestEntrySlice_WriteBars(t *testing.T) {
+	entries := EntrySlice{{1, Word(strings.Repeat("ё", 30)), 10, 0.5}, {2, "a", 1, 0.05}}
+	for _, width := range []int{0, 50, 120} {
+		var b bytes.Buffer
+		if err := entries.WriteBars(&b, width); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines, got %q", lines)
+		}
+		n := utf8.RuneCountInString(lines[0])
+		if n != utf8.RuneCountInString(lines[1]) || width >= 50 && n != width {
+			t.Errorf("width %d: Expected lines of the same width fitting it, got %q", width, lines)
+		}
+		if !strings.HasPrefix(lines[0], strings.Repeat("ё", maxLabelWidth-1)+"…  █") {
+			t.Errorf("Expected a truncated label, got %q", lines[0])
+		}
+	}
+	var b bytes.Buffer
+	if err := (EntrySlice{}).WriteBars(&b, 80); err != nil || b.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q, %v", b.String(), err)
+	}
+}
+
+func TestEntrySlice_WriteSVG(t *testing.T) {
+	entries := GetResultEntries(strings.Repeat("go ", 9)+"rust rust <tag> & c c c", 10, WithTokenizer(FieldsTokenizer))
+	var b bytes.Buffer
+	if err := entries.WriteSVG(&b, 400, 200); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var svg struct {
+		Width int `xml:"width,attr"`
+		Texts []struct {
+			X, Y     float64 `xml:",attr"`
+			FontSize float64 `xml:"font-size,attr"`
+			Title    string  `xml:"title"`
+			Word     string  `xml:",chardata"`
+		} `xml:"text"`
+	}
+	if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+		t.Fatalf("Expected valid XML, got %v\n%s", err, b.String())
+	}
+	if svg.Width != 400 || len(svg.Texts) != len(entries) {
+		t.Fatalf("Expected %d words, got %d", len(entries), len(svg.Texts))
+	}
+	if svg.Texts[0].Word != "go" || svg.Texts[0].FontSize != maxFontSize || svg.Texts[0].Title != "go: 9, 56.25%" {
+		t.Errorf("Expected the largest go first, got %+v", svg.Texts[0])
+	}
+	last := svg.Texts[len(svg.Texts)-1]
+	if last.FontSize != minFontSize || last.Word != "<tag>" && last.Word != "&" {
+		t.Errorf("Expected the smallest rare word last, got %+v", last)
+	}
+
+	var again bytes.Buffer
+	entries.WriteSVG(&again, 400, 200)
+	if again.String() != b.String() {
+		t.Errorf("Expected the same picture for the same entries")
+	}
+	var tiny bytes.Buffer
+	entries.WriteSVG(&tiny, 10, 10)
+	if strings.Contains(tiny.String(), "<text") {
+		t.Errorf("Expected words not fitting to be left out, got %s", tiny.String())
+	}
+}
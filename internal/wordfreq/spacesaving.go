@@ -0,0 +1,173 @@
+package wordfreq
+
+import (
+	"container/heap"
+	"io"
+	"slices"
+)
+
+// Estimate is an approximate number of occurrences of a word:
+// the exact number lies within [Count-Error, Count].
+type Estimate struct {
+	Word  Word
+	Count int
+	Error int
+}
+
+// SpaceSaving finds the most frequent words of a stream of any length
+// in fixed memory with the Space-Saving algorithm (Metwally, Agrawal and
+// El Abbadi, 2005). It monitors a fixed number of words: a word which is
+// not monitored replaces the monitored word with the least count and
+// inherits that count as its possible error.
+//
+// With m monitored words every word occurring more than Total/m times
+// is monitored, and no estimate exceeds the exact count by more than Total/m.
+type SpaceSaving struct {
+	opts     options
+	capacity int
+	h        *ssHeap
+	grams    ngramStream
+	evicted  bool // whether a monitored word has ever been replaced
+	words    int
+	chunk    int
+}
+
+// NewSpaceSaving returns an empty SpaceSaving monitoring at most capacity words,
+// at least one. Memory grows with capacity, not with the length of the text.
+// The number of workers of the options is ignored,
+// the text is always counted by the calling goroutine.
+func NewSpaceSaving(capacity int, opts ...Option) *SpaceSaving {
+	capacity = max(capacity, 1)
+	o := newOptions(opts)
+	return &SpaceSaving{
+		opts:     o,
+		capacity: capacity,
+		grams:    ngramStream{n: o.ngramWords()},
+		h:        &ssHeap{items: make([]ssItem, 0, capacity), index: make(map[Word]int, capacity)},
+		chunk:    DefaultChunkSize,
+	}
+}
+
+// Add counts the words of text as if they followed the words counted before.
+func (s *SpaceSaving) Add(text string) {
+	words, _ := s.opts.tokenizeForms(text)
+	words, _ = s.grams.next(words, nil)
+	for _, w := range words {
+		s.add(w)
+	}
+}
+
+// add counts one word.
+func (s *SpaceSaving) add(w Word) {
+	pos := s.words
+	s.words++
+	h := s.h
+	if i, ok := h.index[w]; ok {
+		h.items[i].count++
+		h.items[i].last = pos
+		heap.Fix(h, i)
+		return
+	}
+	if len(h.items) < s.capacity {
+		heap.Push(h, ssItem{word: w, count: 1, first: pos, last: pos})
+		return
+	}
+	// the word takes the place of the least frequent one
+	least := h.items[0]
+	delete(h.index, least.word)
+	h.items[0] = ssItem{word: w, count: least.count + 1, err: least.count, first: pos, last: pos}
+	h.index[w] = 0
+	heap.Fix(h, 0)
+	s.evicted = true
+}
+
+// ReadFrom counts the words read from r until EOF like Counter.ReadFrom
+// and returns the number of bytes read.
+func (s *SpaceSaving) ReadFrom(r io.Reader) (int64, error) {
+	return readChunks(r, s.chunk, s.Add)
+}
+
+// Total returns the number of words counted.
+func (s *SpaceSaving) Total() int {
+	return s.words
+}
+
+// ErrorBound returns the largest possible error of an estimate:
+// Total divided by the number of monitored words.
+func (s *SpaceSaving) ErrorBound() int {
+	return s.words / s.capacity
+}
+
+// Top returns the estimates of the k most frequent words, all monitored words
+// if k exceeds their number. Words are ordered by estimated count, words with
+// equal estimates by the tie-breaking rule of the options, where positions are
+// those since the word has been monitored.
+//
+// The second result reports whether the returned words are exactly the k most
+// frequent words of the text: it holds when the guaranteed count Count-Error
+// of each of them is not less than the largest possible count of any other word.
+func (s *SpaceSaving) Top(k int) ([]Estimate, bool) {
+	items := s.h.items
+	all := make([]ranked, len(items))
+	errs := make(map[Word]int, len(items))
+	for i, it := range items {
+		all[i] = ranked{it.word, wordStat{count: it.count, first: it.first, last: it.last}}
+		errs[it.word] = it.err
+	}
+	slices.SortFunc(all, rankOrder(s.opts.tieBreak))
+	k = min(max(k, 0), len(all))
+	// the largest possible count of a word left out: the next monitored one
+	// or, once words have been replaced, the least count of a monitored word
+	threshold := 0
+	switch {
+	case k < len(all):
+		threshold = all[k].stat.count
+	case s.evicted:
+		threshold = items[0].count
+	}
+	result := make([]Estimate, k)
+	guaranteed := true
+	for i, r := range all[:k] {
+		result[i] = Estimate{Word: r.word, Count: r.stat.count, Error: errs[r.word]}
+		if r.stat.count-errs[r.word] < threshold {
+			guaranteed = false
+		}
+	}
+	return result, guaranteed
+}
+
+// ssItem is a monitored word.
+type ssItem struct {
+	word        Word
+	count, err  int
+	first, last int
+}
+
+// ssHeap is a min-heap of monitored words by count
+// which keeps track of the positions of the words.
+type ssHeap struct {
+	items []ssItem
+	index map[Word]int
+}
+
+func (h *ssHeap) Len() int           { return len(h.items) }
+func (h *ssHeap) Less(i, j int) bool { return h.items[i].count < h.items[j].count }
+
+func (h *ssHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].word] = i
+	h.index[h.items[j].word] = j
+}
+
+func (h *ssHeap) Push(x any) {
+	it := x.(ssItem)
+	h.index[it.word] = len(h.items)
+	h.items = append(h.items, it)
+}
+
+func (h *ssHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	delete(h.index, last.word)
+	return last
+}
@@ -0,0 +1,85 @@
+package wordfreq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ExampleSpaceSaving
+func ExampleSpaceSaving() {
+	s := NewSpaceSaving(3)
+	s.Add("a b a c a d b e a b")
+	top, guaranteed := s.Top(2)
+	fmt.Println(top, guaranteed, s.ErrorBound())
+	// Output: [{a 4 0} {b 3 2}] false 3
+}
+
+func TestSpaceSaving_Exact(t *testing.T) {
+	text := corpus(3000)
+	s := NewSpaceSaving(10000)
+	s.Add(text)
+	c := NewCounter()
+	c.Add(text)
+	top, guaranteed := s.Top(20)
+	if !guaranteed {
+		t.Errorf("Expected exact counts to be guaranteed")
+	}
+	counts := c.Counts()
+	for i, e := range top {
+		if e.Word != c.Top(20)[i] || e.Count != counts[e.Word] || e.Error != 0 {
+			t.Errorf("Expected %s %d, got %v", c.Top(20)[i], counts[e.Word], e)
+		}
+	}
+}
+
+func TestSpaceSaving_Bounds(t *testing.T) {
+	text := corpus(200000)
+	c := NewCounter()
+	c.Add(text)
+	counts := c.Counts()
+	for _, capacity := range []int{50, 200, 1000} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			s := NewSpaceSaving(capacity)
+			s.ReadFrom(strings.NewReader(text))
+			if s.Total() != c.Total() {
+				t.Fatalf("Expected %d words, got %d", c.Total(), s.Total())
+			}
+			bound := s.ErrorBound()
+			all, _ := s.Top(capacity)
+			for _, e := range all {
+				exact := counts[e.Word]
+				if exact > e.Count || exact < e.Count-e.Error || e.Error > bound {
+					t.Errorf("Expected %d within [%d, %d] and error at most %d", exact, e.Count-e.Error, e.Count, bound)
+				}
+			}
+			// every word more frequent than the bound is monitored
+			for w, n := range counts {
+				if n > bound && !slices.ContainsFunc(all, func(e Estimate) bool { return e.Word == w }) {
+					t.Errorf("Expected %s with %d occurrences to be monitored", w, n)
+				}
+			}
+			top, guaranteed := s.Top(5)
+			got := make(WordSlice, len(top))
+			for i, e := range top {
+				got[i] = e.Word
+			}
+			if guaranteed && !slices.Equal(got, c.Top(5)) {
+				t.Errorf("Expected guaranteed top %v, got %v", c.Top(5), top)
+			}
+		})
+	}
+}
+
+func TestSpaceSaving_NotGuaranteed(t *testing.T) {
+	s := NewSpaceSaving(2)
+	s.Add("a b c d e f")
+	top, guaranteed := s.Top(1)
+	if guaranteed || len(top) != 1 || top[0].Error == 0 {
+		t.Errorf("Expected an uncertain estimate, got %v %v", top, guaranteed)
+	}
+	if top, _ := s.Top(-1); len(top) != 0 {
+		t.Errorf("Expected no estimates, got %v", top)
+	}
+}
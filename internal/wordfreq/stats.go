@@ -0,0 +1,197 @@
+package wordfreq
+
+import (
+	"io"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// TextStats describes a text: the sizes of its words, sentences and vocabulary.
+// The readability indices computed from them were made for English text
+// and only roughly apply to other languages.
+type TextStats struct {
+	Words             int // number of words
+	UniqueWords       int // number of distinct words
+	Hapaxes           int // number of words occurring once, hapax legomena
+	Characters        int // number of letters and digits of the words
+	Syllables         int // estimated number of syllables of the words
+	PolysyllabicWords int // number of words of three syllables or more
+	Sentences         int
+}
+
+// TypeTokenRatio returns the number of distinct words per word, 0 for no words.
+// It measures the variety of the vocabulary but falls as a text grows.
+func (s TextStats) TypeTokenRatio() float64 {
+	return ratio(s.UniqueWords, s.Words)
+}
+
+// AverageWordLength returns the average number of letters and digits of a word.
+func (s TextStats) AverageWordLength() float64 {
+	return ratio(s.Characters, s.Words)
+}
+
+// AverageSentenceLength returns the average number of words of a sentence.
+func (s TextStats) AverageSentenceLength() float64 {
+	return ratio(s.Words, s.Sentences)
+}
+
+// FleschReadingEase returns the Flesch reading-ease score: from about 100
+// for very easy text down to 0 and below for very difficult text.
+func (s TextStats) FleschReadingEase() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 206.835 - 1.015*s.AverageSentenceLength() - 84.6*ratio(s.Syllables, s.Words)
+}
+
+// FleschKincaidGrade returns the Flesch-Kincaid grade level:
+// the US school grade needed to understand the text.
+func (s TextStats) FleschKincaidGrade() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 0.39*s.AverageSentenceLength() + 11.8*ratio(s.Syllables, s.Words) - 15.59
+}
+
+// GunningFog returns the Gunning fog index, a grade level
+// counting words of three syllables or more as complex.
+func (s TextStats) GunningFog() float64 {
+	return 0.4 * (s.AverageSentenceLength() + 100*ratio(s.PolysyllabicWords, s.Words))
+}
+
+// ColemanLiau returns the Coleman-Liau index, a grade level
+// computed from characters rather than syllables.
+func (s TextStats) ColemanLiau() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 5.88*s.AverageWordLength() - 29.6*ratio(s.Sentences, s.Words) - 15.8
+}
+
+// AutomatedReadability returns the automated readability index (ARI), a grade level.
+func (s TextStats) AutomatedReadability() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 4.71*s.AverageWordLength() + 0.5*s.AverageSentenceLength() - 21.43
+}
+
+// SMOG returns the SMOG grade, made for texts of 30 sentences or more.
+func (s TextStats) SMOG() float64 {
+	if s.Sentences == 0 {
+		return 0
+	}
+	return 1.043*math.Sqrt(float64(s.PolysyllabicWords)*30/float64(s.Sentences)) + 3.1291
+}
+
+// ratio returns a/b, 0 if b is 0.
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// StatsCounter computes the statistics of a text which may come in parts.
+// Words are split by the tokenizer of the options, other options are ignored,
+// so stop words count as words. A sentence ends at a full stop, '!', '?', '…'
+// or another sentence terminator after a word, and so does the text,
+// so abbreviations like "e.g." end sentences too.
+// The zero value is not usable, create it with NewStatsCounter.
+type StatsCounter struct {
+	opts       options
+	counts     map[Word]int
+	stats      TextStats
+	inSentence bool // whether words have followed the end of the last sentence
+	chunk      int
+}
+
+// NewStatsCounter returns an empty StatsCounter with the given options.
+func NewStatsCounter(opts ...Option) *StatsCounter {
+	return &StatsCounter{opts: newOptions(opts), counts: map[Word]int{}, chunk: DefaultChunkSize}
+}
+
+// GetTextStats returns the statistics of s.
+func GetTextStats(s string, opts ...Option) TextStats {
+	c := NewStatsCounter(opts...)
+	c.Add(s)
+	return c.Stats()
+}
+
+// Add counts s as if it followed the text counted before.
+func (c *StatsCounter) Add(s string) {
+	for _, r := range s {
+		switch {
+		case isWordRune(r):
+			c.inSentence = true
+		case c.inSentence && isSentenceEnd(r):
+			c.stats.Sentences++
+			c.inSentence = false
+		}
+	}
+	for _, w := range c.opts.tokenizer.Tokenize(s) {
+		c.counts[w]++
+		switch c.counts[w] {
+		case 1:
+			c.stats.UniqueWords++
+			c.stats.Hapaxes++
+		case 2:
+			c.stats.Hapaxes--
+		}
+		c.stats.Words++
+		n := syllables(w)
+		c.stats.Syllables += n
+		if n >= 3 {
+			c.stats.PolysyllabicWords++
+		}
+		for _, r := range string(w) {
+			if isWordRune(r) {
+				c.stats.Characters++
+			}
+		}
+	}
+}
+
+// ReadFrom counts the text read from r until EOF like Counter.ReadFrom
+// and returns the number of bytes read.
+func (c *StatsCounter) ReadFrom(r io.Reader) (int64, error) {
+	return readChunks(r, c.chunk, c.Add)
+}
+
+// Stats returns the statistics of the text counted so far,
+// an unfinished last sentence counted as a sentence.
+func (c *StatsCounter) Stats() TextStats {
+	s := c.stats
+	if c.inSentence {
+		s.Sentences++
+	}
+	return s
+}
+
+// syllableVowels - Latin and Cyrillic vowels, every group of them is a syllable
+const syllableVowels = "aeiouyàâäéèêëîïôöùûüæœáíóúаеёиоуыэюя"
+
+// syllables estimates the number of syllables of w as the number of groups
+// of vowels, not counting a silent final English "e" as in "make".
+// A word has at least one syllable.
+func syllables(w Word) int {
+	rs := []rune(strings.ToLower(string(w)))
+	n, vowel := 0, false
+	for _, r := range rs {
+		v := strings.ContainsRune(syllableVowels, r)
+		if v && !vowel {
+			n++
+		}
+		vowel = v
+	}
+	if l := len(rs); n > 1 && l > 2 && rs[l-1] == 'e' && !strings.ContainsRune(syllableVowels, rs[l-2]) && rs[l-2] != 'l' {
+		n--
+	}
+	return max(n, 1)
+}
+
+// isSentenceEnd reports whether r ends a sentence.
+func isSentenceEnd(r rune) bool {
+	return r == '…' || unicode.Is(unicode.Sentence_Terminal, r)
+}
@@ -0,0 +1,127 @@
+package wordfreq
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// ExampleGetTextStats
+func ExampleGetTextStats() {
+	s := GetTextStats("The cat sat on the mat. The dog ate my homework!")
+	fmt.Println(s.Words, s.UniqueWords, s.Hapaxes, s.Sentences, s.Syllables)
+	fmt.Printf("%.2f %.2f\n", s.TypeTokenRatio(), s.FleschReadingEase())
+	// Output:
+	// 11 9 8 2 13
+	// 0.82 101.27
+}
+
+func Test_syllables(t *testing.T) {
+	tests := []struct {
+		word     Word
+		expected int
+	}{
+		{"cat", 1},
+		{"make", 1},
+		{"table", 2},
+		{"the", 1},
+		{"beautiful", 3},
+		{"window", 2},
+		{"readability", 5},
+		{"rhythm", 1},
+		{"молоко", 3},
+		{"42", 1},
+	}
+
+	for _, d := range tests {
+		t.Run(string(d.word), func(t *testing.T) {
+			if got := syllables(d.word); got != d.expected {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestStatsCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected TextStats
+	}{
+		{"empty", "", TextStats{}},
+		{"punctuation only", "... !?", TextStats{}},
+		{"unfinished sentence", "one two one", TextStats{Words: 3, UniqueWords: 2, Hapaxes: 1, Characters: 9, Syllables: 3, Sentences: 1}},
+		{"sentences", "Wait... What?! Really. ", TextStats{Words: 3, UniqueWords: 3, Hapaxes: 3, Characters: 14, Syllables: 4, Sentences: 3}},
+		{"other terminators", "Да… Нет。", TextStats{Words: 2, UniqueWords: 2, Hapaxes: 2, Characters: 5, Syllables: 2, Sentences: 2}},
+		{"polysyllables", "Beautiful readability.", TextStats{Words: 2, UniqueWords: 2, Hapaxes: 2, Characters: 20, Syllables: 8, PolysyllabicWords: 2, Sentences: 1}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := GetTextStats(d.text); got != d.expected {
+				t.Errorf("Expected %+v, got %+v", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestStatsCounter_stream(t *testing.T) {
+	text := strings.Repeat("A short line. Another line without an end ", 20)
+	whole := GetTextStats(text)
+	parts := NewStatsCounter()
+	for w := range strings.FieldsSeq(text) {
+		parts.Add(w + " ")
+	}
+	read := NewStatsCounter()
+	read.chunk = 8
+	if _, err := read.ReadFrom(strings.NewReader(text)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, got := range []TextStats{parts.Stats(), read.Stats()} {
+		if got != whole {
+			t.Errorf("Expected %+v, got %+v", whole, got)
+		}
+	}
+	if whole.Sentences != 21 || whole.UniqueWords != 7 || whole.Hapaxes != 0 {
+		t.Errorf("Expected 21 sentences, 7 unique words and no hapaxes, got %+v", whole)
+	}
+	// split at whitespace "line." and "line" differ
+	if got := GetTextStats(text, WithTokenizer(FieldsTokenizer)); got.UniqueWords != 8 || got.Characters != whole.Characters {
+		t.Errorf("Expected 8 unique words and %d characters, got %+v", whole.Characters, got)
+	}
+}
+
+func TestTextStats_indices(t *testing.T) {
+	s := TextStats{Words: 100, UniqueWords: 60, Characters: 470, Syllables: 150, PolysyllabicWords: 10, Sentences: 5}
+	tests := []struct {
+		name          string
+		got, expected float64
+	}{
+		{"type-token ratio", s.TypeTokenRatio(), 0.6},
+		{"average word length", s.AverageWordLength(), 4.7},
+		{"average sentence length", s.AverageSentenceLength(), 20},
+		{"Flesch reading ease", s.FleschReadingEase(), 206.835 - 1.015*20 - 84.6*1.5},
+		{"Flesch-Kincaid grade", s.FleschKincaidGrade(), 0.39*20 + 11.8*1.5 - 15.59},
+		{"Gunning fog", s.GunningFog(), 0.4 * (20 + 10)},
+		{"Coleman-Liau", s.ColemanLiau(), 0.0588*470 - 0.296*5 - 15.8},
+		{"ARI", s.AutomatedReadability(), 4.71*4.7 + 0.5*20 - 21.43},
+		{"SMOG", s.SMOG(), 1.043*math.Sqrt(10*30/5) + 3.1291},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if math.Abs(d.got-d.expected) > 1e-9 {
+				t.Errorf("Expected %v, got %v", d.expected, d.got)
+			}
+		})
+	}
+
+	var empty TextStats
+	for _, v := range []float64{empty.TypeTokenRatio(), empty.FleschReadingEase(), empty.FleschKincaidGrade(),
+		empty.GunningFog(), empty.ColemanLiau(), empty.AutomatedReadability(), empty.SMOG()} {
+		if v != 0 {
+			t.Errorf("Expected 0 for no words, got %v", v)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package wordfreq
+
+import "strings"
+
+// EnglishStemmer is the Snowball English (Porter2) stemmer:
+// "running" and "runs" become "run", "generously" becomes "generous".
+// Common irregular verb forms are mapped to their infinitive first, so "ran" is "run" too.
+// Words which are not written in lower case Latin letters are left as they are.
+type EnglishStemmer struct{}
+
+// Normalize returns the stem of w.
+func (EnglishStemmer) Normalize(w Word) Word {
+	s := string(w)
+	if lemma, ok := englishIrregular[s]; ok {
+		s = lemma
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < 'a' || s[i] > 'z') && s[i] != '\'' {
+			return Word(s)
+		}
+	}
+	return Word(stemEnglish(s))
+}
+
+// englishIrregular - past tense and participle forms of common irregular verbs.
+// Forms which are also common words of their own, like "left", "saw", "rose",
+// "felt", "found", "won", "fell", "spoke" or "drove", are not mapped.
+var englishIrregular = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be",
+	"has": "have", "had": "have", "did": "do", "done": "do",
+	"ran": "run", "went": "go", "gone": "go", "came": "come", "seen": "see",
+	"took": "take", "taken": "take", "gave": "give", "given": "give", "made": "make",
+	"said": "say", "got": "get", "gotten": "get", "knew": "know", "known": "know",
+	"thought": "think", "told": "tell", "brought": "bring", "bought": "buy", "began": "begin", "begun": "begin", "kept": "keep", "held": "hold", "wrote": "write", "written": "write", "stood": "stand",
+	"heard": "hear", "meant": "mean", "met": "meet", "paid": "pay", "sat": "sit",
+	"spoken": "speak", "led": "lead", "grew": "grow", "grown": "grow",
+	"lost": "lose", "fallen": "fall", "sent": "send", "built": "build",
+	"understood": "understand", "drew": "draw", "drawn": "draw", "broke": "break",
+	"broken": "break", "spent": "spend", "risen": "rise", "driven": "drive", "ate": "eat",
+	"eaten": "eat", "wore": "wear", "worn": "wear",
+	"chose": "choose", "chosen": "choose", "sang": "sing", "sung": "sing", "swam": "swim",
+	"swum": "swim", "flew": "fly", "flown": "fly", "threw": "throw", "thrown": "throw",
+	"fought": "fight", "caught": "catch", "taught": "teach", "slept": "sleep",
+	"sold": "sell", "forgot": "forget", "forgotten": "forget",
+}
+
+// englishExceptions - words with special stems, checked before the steps
+var englishExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// englishExceptions2 - words left as they are after step 1a
+var englishExceptions2 = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+// porter holds a word being stemmed and the starts of its regions R1 and R2.
+type porter struct {
+	b      []byte
+	r1, r2 int
+}
+
+// stemEnglish returns the Porter2 stem of a lower case word.
+func stemEnglish(s string) string {
+	if len(s) <= 2 {
+		return s
+	}
+	if e, ok := englishExceptions[s]; ok {
+		return e
+	}
+	s = strings.TrimPrefix(s, "'")
+	p := &porter{b: []byte(s)}
+	for i, c := range p.b {
+		if c == 'y' && (i == 0 || p.isVowel(i-1)) {
+			p.b[i] = 'Y'
+		}
+	}
+	p.regions()
+	p.step0()
+	p.step1a()
+	if englishExceptions2[string(p.b)] {
+		return string(p.b)
+	}
+	p.step1b()
+	p.step1c()
+	p.step2()
+	p.step3()
+	p.step4()
+	p.step5()
+	return strings.ReplaceAll(string(p.b), "Y", "y")
+}
+
+// isVowel reports whether the letter at i is a vowel.
+func (p *porter) isVowel(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// regions finds R1 and R2: R1 follows the first non-vowel following a vowel,
+// R2 is R1 of R1. Words starting with gener, commun or arsen have R1 after them.
+func (p *porter) regions() {
+	p.r1 = len(p.b)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(p.b), prefix) {
+			p.r1 = len(prefix)
+		}
+	}
+	if p.r1 == len(p.b) {
+		p.r1 = p.regionAfter(0)
+	}
+	p.r2 = p.regionAfter(p.r1)
+}
+
+// regionAfter returns the index after the first non-vowel following a vowel at or after start.
+func (p *porter) regionAfter(start int) int {
+	for i := start + 1; i < len(p.b); i++ {
+		if !p.isVowel(i) && p.isVowel(i-1) {
+			return i + 1
+		}
+	}
+	return len(p.b)
+}
+
+// hasSuffix reports whether the word ends with suffix.
+func (p *porter) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(p.b), suffix)
+}
+
+// longest returns the longest of suffixes the word ends with, "" if none.
+func (p *porter) longest(suffixes ...string) string {
+	best := ""
+	for _, s := range suffixes {
+		if len(s) > len(best) && p.hasSuffix(s) {
+			best = s
+		}
+	}
+	return best
+}
+
+// replace replaces the suffix of length n by repl.
+func (p *porter) replace(n int, repl string) {
+	p.b = append(p.b[:len(p.b)-n], repl...)
+}
+
+// containsVowel reports whether the letters before end contain a vowel.
+func (p *porter) containsVowel(end int) bool {
+	for i := 0; i < end; i++ {
+		if p.isVowel(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShortSyllable reports whether the letters before end finish with a short syllable:
+// a non-vowel, a vowel and a non-vowel other than w, x and Y,
+// or a vowel and a non-vowel at the beginning of the word.
+func (p *porter) endsShortSyllable(end int) bool {
+	switch {
+	case end == 2:
+		return p.isVowel(0) && !p.isVowel(1)
+	case end >= 3:
+		c := p.b[end-1]
+		return !p.isVowel(end-3) && p.isVowel(end-2) && !p.isVowel(end-1) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+// isShort reports whether the word ends with a short syllable and R1 is empty.
+func (p *porter) isShort() bool {
+	return p.r1 >= len(p.b) && p.endsShortSyllable(len(p.b))
+}
+
+// step0 removes the possessive suffixes.
+func (p *porter) step0() {
+	if s := p.longest("'", "'s", "'s'"); s != "" {
+		p.replace(len(s), "")
+	}
+}
+
+// step1a handles plurals.
+func (p *porter) step1a() {
+	switch s := p.longest("sses", "ied", "ies", "s", "us", "ss"); s {
+	case "sses":
+		p.replace(len(s), "ss")
+	case "ied", "ies":
+		if len(p.b) > 4 {
+			p.replace(len(s), "i")
+		} else {
+			p.replace(len(s), "ie")
+		}
+	case "s":
+		if p.containsVowel(len(p.b) - 2) {
+			p.replace(1, "")
+		}
+	}
+}
+
+// step1b handles past tense and gerunds.
+func (p *porter) step1b() {
+	switch s := p.longest("eed", "eedly", "ed", "edly", "ing", "ingly"); s {
+	case "eed", "eedly":
+		if len(p.b)-len(s) >= p.r1 {
+			p.replace(len(s), "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !p.containsVowel(len(p.b) - len(s)) {
+			return
+		}
+		p.replace(len(s), "")
+		switch {
+		case p.hasSuffix("at") || p.hasSuffix("bl") || p.hasSuffix("iz"):
+			p.b = append(p.b, 'e')
+		case p.longest("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			p.b = p.b[:len(p.b)-1]
+		case p.isShort():
+			p.b = append(p.b, 'e')
+		}
+	}
+}
+
+// step1c replaces a final y after a non-vowel which is not the first letter by i.
+func (p *porter) step1c() {
+	n := len(p.b)
+	if n > 2 && (p.b[n-1] == 'y' || p.b[n-1] == 'Y') && !p.isVowel(n-2) {
+		p.b[n-1] = 'i'
+	}
+}
+
+// step2Suffixes - replacements of step 2
+var step2Suffixes = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+	"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+	"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous",
+	"ousness": "ous", "iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+	"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+}
+
+// step3Suffixes - replacements of step 3
+var step3Suffixes = map[string]string{
+	"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic",
+	"ical": "ic", "ful": "", "ness": "", "ative": "",
+}
+
+// step4Suffixes - suffixes removed by step 4
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+// longestOf returns the longest key of m the word ends with.
+func (p *porter) longestOf(m map[string]string) string {
+	best := ""
+	for s := range m {
+		if len(s) > len(best) && p.hasSuffix(s) {
+			best = s
+		}
+	}
+	return best
+}
+
+// step2 replaces derivational suffixes in R1.
+func (p *porter) step2() {
+	s := p.longestOf(step2Suffixes)
+	if s == "" || len(p.b)-len(s) < p.r1 {
+		return
+	}
+	stem := len(p.b) - len(s)
+	switch s {
+	case "ogi":
+		if stem == 0 || p.b[stem-1] != 'l' {
+			return
+		}
+	case "li":
+		if stem == 0 || !strings.ContainsRune("cdeghkmnrt", rune(p.b[stem-1])) {
+			return
+		}
+	}
+	p.replace(len(s), step2Suffixes[s])
+}
+
+// step3 replaces more derivational suffixes in R1, ative in R2.
+func (p *porter) step3() {
+	s := p.longestOf(step3Suffixes)
+	if s == "" || len(p.b)-len(s) < p.r1 || s == "ative" && len(p.b)-len(s) < p.r2 {
+		return
+	}
+	p.replace(len(s), step3Suffixes[s])
+}
+
+// step4 removes suffixes in R2, ion only after s or t.
+func (p *porter) step4() {
+	s := p.longest(step4Suffixes...)
+	if s == "" || len(p.b)-len(s) < p.r2 {
+		return
+	}
+	stem := len(p.b) - len(s)
+	if s == "ion" && (stem == 0 || p.b[stem-1] != 's' && p.b[stem-1] != 't') {
+		return
+	}
+	p.replace(len(s), "")
+}
+
+// step5 removes a final e in R2, or in R1 not after a short syllable,
+// and a final l after l in R2.
+func (p *porter) step5() {
+	n := len(p.b)
+	switch {
+	case p.hasSuffix("e"):
+		if n-1 >= p.r2 || n-1 >= p.r1 && !p.endsShortSyllable(n-1) {
+			p.b = p.b[:n-1]
+		}
+	case p.hasSuffix("ll"):
+		if n-1 >= p.r2 {
+			p.b = p.b[:n-1]
+		}
+	}
+}
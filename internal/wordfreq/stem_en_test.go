@@ -0,0 +1,49 @@
+package wordfreq
+
+import "testing"
+
+func TestEnglishStemmer_Normalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []Word
+		expected Word
+	}{
+		{"plurals", []Word{"caresses", "caress"}, "caress"},
+		{"ies", []Word{"ponies", "pony"}, "poni"},
+		{"short ies", []Word{"ties", "tie"}, "tie"},
+		{"gerund doubling", []Word{"running", "runs", "run"}, "run"},
+		{"irregular", []Word{"ran", "run"}, "run"},
+		{"ambiguous irregular", []Word{"left"}, "left"},
+		{"ambiguous irregular noun", []Word{"saw", "saws"}, "saw"},
+		{"ambiguous irregular verb", []Word{"found", "founded"}, "found"},
+		{"past tense", []Word{"cried", "cries"}, "cri"},
+		{"short stem gets e", []Word{"filing", "file"}, "file"},
+		{"eed in R1", []Word{"agreed"}, "agre"},
+		{"step 2", []Word{"relational"}, "relat"},
+		{"step 2 li", []Word{"generously"}, "generous"},
+		{"step 3", []Word{"hopeful", "hopefulness"}, "hope"},
+		{"step 4", []Word{"consignment", "consign"}, "consign"},
+		{"ion after t", []Word{"adoption"}, "adopt"},
+		{"commun region", []Word{"communism"}, "communism"},
+		{"y after non-vowel", []Word{"happy"}, "happi"},
+		{"li not after valid ending", []Word{"happily"}, "happili"},
+		{"possessive", []Word{"cat's", "cats"}, "cat"},
+		{"exceptions", []Word{"dying"}, "die"},
+		{"exceptional plural", []Word{"skies", "sky"}, "sky"},
+		{"invariable", []Word{"news"}, "news"},
+		{"left after step 1a", []Word{"proceed"}, "proceed"},
+		{"short word", []Word{"at"}, "at"},
+		{"not latin", []Word{"café"}, "café"},
+		{"number", []Word{"2024"}, "2024"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			for _, w := range d.words {
+				if got := (EnglishStemmer{}).Normalize(w); got != d.expected {
+					t.Errorf("%q: Expected %q, got %q", w, d.expected, got)
+				}
+			}
+		})
+	}
+}
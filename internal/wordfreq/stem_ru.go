@@ -0,0 +1,154 @@
+package wordfreq
+
+import (
+	"slices"
+	"strings"
+)
+
+// RussianStemmer is the Snowball Russian stemmer:
+// "книги", "книгой" and "книга" become "книг".
+// Words which are not written in lower case Cyrillic letters are left as they are.
+type RussianStemmer struct{}
+
+// Normalize returns the stem of w.
+func (RussianStemmer) Normalize(w Word) Word {
+	for _, r := range w {
+		if (r < 'а' || r > 'я') && r != 'ё' && r != '-' {
+			return w
+		}
+	}
+	return Word(stemRussian(string(w)))
+}
+
+// Russian endings, the groups marked with "after а or я" are removed
+// only when they follow one of these letters, which stays.
+var (
+	ruPerfectiveGerund1 = []string{"в", "вши", "вшись"} // after а or я
+	ruPerfectiveGerund2 = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+	ruAdjective         = []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	}
+	ruParticiple1 = []string{"ем", "нн", "вш", "ющ", "щ"} // after а or я
+	ruParticiple2 = []string{"ивш", "ывш", "ующ"}
+	ruReflexive   = []string{"ся", "сь"}
+	ruVerb1       = []string{ // after а or я
+		"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно",
+	}
+	ruVerb2 = []string{
+		"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+		"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+	}
+	ruNoun = []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	}
+	ruSuperlative  = []string{"ейш", "ейше"}
+	ruDerivational = []string{"ост", "ость"}
+)
+
+// isRussianVowel reports whether r is a Russian vowel.
+func isRussianVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// stemRussian returns the Snowball stem of a lower case Russian word.
+func stemRussian(s string) string {
+	w := []rune(strings.ReplaceAll(s, "ё", "е"))
+	// RV follows the first vowel, R2 is R1 of R1 where R1 follows
+	// the first non-vowel following a vowel
+	rv := slices.IndexFunc(w, isRussianVowel) + 1
+	if rv == 0 {
+		return string(w)
+	}
+	r2 := ruRegionAfter(w, ruRegionAfter(w, 0))
+	stem, rest := w[:rv], w[rv:]
+
+	// step 1
+	if end, ok := ruRemove(rest, ruPerfectiveGerund1, ruPerfectiveGerund2); ok {
+		rest = end
+	} else {
+		rest, _ = ruRemove(rest, nil, ruReflexive)
+		if end, ok := ruRemoveAdjectival(rest); ok {
+			rest = end
+		} else if end, ok := ruRemove(rest, ruVerb1, ruVerb2); ok {
+			rest = end
+		} else {
+			rest, _ = ruRemove(rest, nil, ruNoun)
+		}
+	}
+	// step 2
+	if len(rest) > 0 && rest[len(rest)-1] == 'и' {
+		rest = rest[:len(rest)-1]
+	}
+	// step 3: derivational endings in R2
+	if end, ok := ruRemove(rest, nil, ruDerivational); ok && rv+len(end) >= r2 {
+		rest = end
+	}
+	// step 4
+	switch {
+	case ruHasSuffix(rest, "нн"):
+		rest = rest[:len(rest)-1]
+	case ruHasSuffix(rest, "ь"):
+		rest = rest[:len(rest)-1]
+	default:
+		if end, ok := ruRemove(rest, nil, ruSuperlative); ok {
+			rest = end
+			if ruHasSuffix(rest, "нн") {
+				rest = rest[:len(rest)-1]
+			}
+		}
+	}
+	return string(stem) + string(rest)
+}
+
+// ruRegionAfter returns the index after the first non-vowel following a vowel at or after start.
+func ruRegionAfter(w []rune, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isRussianVowel(w[i]) && isRussianVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// ruHasSuffix reports whether w ends with suffix.
+func ruHasSuffix(w []rune, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+// ruRemove removes the longest ending of rv, the part of the word in RV, found in
+// group1 or group2. An ending of group1 is removed only after а or я, which must
+// lie in RV too; when the longest ending does not follow them nothing is removed.
+func ruRemove(rv []rune, group1, group2 []string) ([]rune, bool) {
+	best, inGroup1 := 0, false
+	for i, group := range [2][]string{group1, group2} {
+		for _, e := range group {
+			if n := len([]rune(e)); n > best && ruHasSuffix(rv, e) {
+				best, inGroup1 = n, i == 0
+			}
+		}
+	}
+	if best == 0 {
+		return rv, false
+	}
+	if inGroup1 {
+		if best >= len(rv) {
+			return rv, false
+		}
+		if before := rv[len(rv)-best-1]; before != 'а' && before != 'я' {
+			return rv, false
+		}
+	}
+	return rv[:len(rv)-best], true
+}
+
+// ruRemoveAdjectival removes an adjective ending optionally preceded by a participle one.
+func ruRemoveAdjectival(rv []rune) ([]rune, bool) {
+	end, ok := ruRemove(rv, nil, ruAdjective)
+	if !ok {
+		return rv, false
+	}
+	end, _ = ruRemove(end, ruParticiple1, ruParticiple2)
+	return end, true
+}
@@ -0,0 +1,37 @@
+package wordfreq
+
+import "testing"
+
+func TestRussianStemmer_Normalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []Word
+		expected Word
+	}{
+		{"noun cases", []Word{"книга", "книги", "книгой", "книгу"}, "книг"},
+		{"plural nouns", []Word{"вагоны", "вагонов", "вагон"}, "вагон"},
+		{"noun ending ами", []Word{"машинами", "машина"}, "машин"},
+		{"adjective", []Word{"красивый", "красивая", "красивые"}, "красив"},
+		{"superlative", []Word{"важнейшие"}, "важн"},
+		{"participle after а", []Word{"бегавшая"}, "бега"},
+		{"verb after а", []Word{"сказал", "сказала", "сказали", "сказать"}, "сказа"},
+		{"verb ending ешь", []Word{"читаешь"}, "чита"},
+		{"reflexive", []Word{"делаться"}, "дела"},
+		{"participle", []Word{"говоривший"}, "говор"},
+		{"derivational in R2", []Word{"молодость"}, "молод"},
+		{"ё is е", []Word{"ёлка", "елка"}, "елк"},
+		{"no vowel", []Word{"вз"}, "вз"},
+		{"not lower case", []Word{"Москва"}, "Москва"},
+		{"latin", []Word{"books"}, "books"},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			for _, w := range d.words {
+				if got := (RussianStemmer{}).Normalize(w); got != d.expected {
+					t.Errorf("%q: Expected %q, got %q", w, d.expected, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package wordfreq
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownLanguage is returned by LoadStopWords and NewStemmer for unsupported languages.
+var ErrUnknownLanguage = errors.New("unsupported language")
+
+//go:embed stopwords/*.txt
+var stopWordFiles embed.FS
+
+// StopWords is a set of words left out of counting.
+// Words are stored as UnicodeTokenizer produces them: NFC normalized and case folded.
+type StopWords map[Word]struct{}
+
+// StopWordLanguages returns the languages of the built-in lists, e.g. "en".
+func StopWordLanguages() []string {
+	entries, _ := stopWordFiles.ReadDir("stopwords")
+	result := make([]string, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	slices.Sort(result)
+	return result
+}
+
+// LoadStopWords returns the built-in list of the language: "en" or "ru".
+func LoadStopWords(lang string) (StopWords, error) {
+	f, err := stopWordFiles.Open("stopwords/" + strings.ToLower(strings.TrimSpace(lang)) + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q, use one of %s", ErrUnknownLanguage, lang, strings.Join(StopWordLanguages(), ", "))
+	}
+	defer f.Close()
+	return ReadStopWords(f)
+}
+
+// ReadStopWords reads a list of stop words separated by whitespace,
+// lines starting with '#' are comments. Words are normalized like
+// UnicodeTokenizer does, so "The" in the list matches "the" in the text.
+func ReadStopWords(r io.Reader) (StopWords, error) {
+	result := StopWords{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, w := range (UnicodeTokenizer{}).Tokenize(line) {
+			result[w] = struct{}{}
+		}
+	}
+	return result, scanner.Err()
+}
+
+// Contains reports whether w is a stop word. Words not split by UnicodeTokenizer
+// are compared as it would return them, so "The" and "the," are stop words if "the" is.
+func (s StopWords) Contains(w Word) bool {
+	if _, ok := s[w]; ok {
+		return true
+	}
+	words := (UnicodeTokenizer{}).Tokenize(string(w))
+	if len(words) != 1 || words[0] == w {
+		return false
+	}
+	_, ok := s[words[0]]
+	return ok
+}
+
+// Merge adds the words of other to s.
+func (s StopWords) Merge(other StopWords) {
+	for w := range other {
+		s[w] = struct{}{}
+	}
+}
+
+// filter removes the stop words from words in place. Words already normalized
+// by UnicodeTokenizer are looked up as they are.
+func (s StopWords) filter(words []Word, normalized bool) []Word {
+	if len(s) == 0 {
+		return words
+	}
+	if normalized {
+		return slices.DeleteFunc(words, func(w Word) bool {
+			_, ok := s[w]
+			return ok
+		})
+	}
+	return slices.DeleteFunc(words, s.Contains)
+}
@@ -0,0 +1,96 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ExampleWithStopWords
+func ExampleWithStopWords() {
+	en, _ := LoadStopWords("en")
+	fmt.Println(GetResultWordsSlice("The cat and the dog and THE bird saw a cat", 2, WithStopWords(en)))
+	// Output: [cat bird]
+}
+
+func TestLoadStopWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		contains []Word
+		err      error
+	}{
+		{"english", "en", []Word{"the", "and", "don't", "a"}, nil},
+		{"russian", "RU", []Word{"и", "в", "не", "что", "её"}, nil},
+		{"unknown", "xx", nil, ErrUnknownLanguage},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, err := LoadStopWords(d.lang)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			for _, w := range d.contains {
+				if !got.Contains(w) {
+					t.Errorf("Expected %q to be a stop word", w)
+				}
+			}
+			if got.Contains("#") || got.Contains("english") || got.Contains("russian") {
+				t.Errorf("Expected comments to be skipped")
+			}
+		})
+	}
+}
+
+func TestStopWordLanguages(t *testing.T) {
+	if got := StopWordLanguages(); !slices.Equal(got, []string{"en", "ru"}) {
+		t.Errorf("Expected [en ru], got %v", got)
+	}
+}
+
+func TestReadStopWords(t *testing.T) {
+	got, err := ReadStopWords(strings.NewReader("# custom list\nFoo bar\n  Ёлка,   baz\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 4 || !got.Contains("foo") || !got.Contains("ёлка") || !got.Contains("baz") {
+		t.Errorf("Expected foo, bar, ёлка and baz, got %v", got)
+	}
+	if !got.Contains("FOO,") || !got.Contains("«Ёлка»") || got.Contains("foo-bar") {
+		t.Errorf("Expected case and punctuation around words to be ignored")
+	}
+}
+
+func TestWithStopWords(t *testing.T) {
+	en, _ := LoadStopWords("en")
+	ru, _ := LoadStopWords("ru")
+	both := StopWords{}
+	both.Merge(en)
+	both.Merge(ru)
+	const text = "The cat и кот, the cat и кот, a dog"
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected WordSlice
+	}{
+		{"none", nil, WordSlice{"cat", "the", "и", "кот", "a", "dog"}},
+		{"english", []Option{WithStopWords(en)}, WordSlice{"cat", "и", "кот", "dog"}},
+		{"both", []Option{WithStopWords(both)}, WordSlice{"cat", "кот", "dog"}},
+		{"parallel", []Option{WithStopWords(both), WithWorkers(2)}, WordSlice{"cat", "кот", "dog"}},
+		{"fields", []Option{WithStopWords(both), WithTokenizer(FieldsTokenizer)}, WordSlice{"cat", "кот,", "dog"}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewCounter(d.opts...)
+			c.chunk = 16
+			c.ReadFrom(strings.NewReader(text))
+			if got := c.Top(10); !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
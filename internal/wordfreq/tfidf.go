@@ -0,0 +1,106 @@
+package wordfreq
+
+import (
+	"cmp"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Term is a word of a document with its TF-IDF statistics.
+type Term struct {
+	Word  Word
+	Count int     // occurrences in the document
+	TF    float64 // term frequency: Count divided by the number of words of the document
+	DF    int     // document frequency: the number of documents with the word
+	TFIDF float64 // TF multiplied by the inverse document frequency ln(N/DF) of N documents
+}
+
+// DocumentTerms are the most distinctive words of a named document.
+type DocumentTerms struct {
+	Name  string
+	Terms []Term
+}
+
+// Collection counts the words of several documents to find the words
+// distinguishing every document from the others by TF-IDF.
+// The zero value is not usable, create it with NewCollection.
+type Collection struct {
+	opts  []Option // options of the counters of documents
+	names []string
+	docs  []*Counter
+	df    map[Word]int
+}
+
+// NewCollection returns an empty Collection counting the words of every document
+// like a Counter with the given options.
+func NewCollection(opts ...Option) *Collection {
+	return &Collection{opts: opts, df: map[Word]int{}}
+}
+
+// Add counts the words of a document read from r until EOF.
+// On error the document is left out.
+func (c *Collection) Add(name string, r io.Reader) error {
+	doc := NewCounter(c.opts...)
+	if _, err := doc.ReadFrom(r); err != nil {
+		return err
+	}
+	c.names = append(c.names, name)
+	c.docs = append(c.docs, doc)
+	for w := range doc.stats {
+		c.df[w]++
+	}
+	return nil
+}
+
+// AddString counts the words of a document given as a string.
+func (c *Collection) AddString(name, s string) {
+	c.Add(name, strings.NewReader(s))
+}
+
+// Len returns the number of documents.
+func (c *Collection) Len() int {
+	return len(c.docs)
+}
+
+// DocumentFrequency returns the number of documents with the word w.
+func (c *Collection) DocumentFrequency(w Word) int {
+	return c.df[w]
+}
+
+// Top returns the k words of every document with the highest TF-IDF, documents
+// in the order they were added. Words with equal TF-IDF are ordered by count,
+// then lexicographically. Words occurring in every document do not distinguish
+// any of them and are left out, so are all words of a single document.
+func (c *Collection) Top(k int) []DocumentTerms {
+	result := make([]DocumentTerms, len(c.docs))
+	for i, doc := range c.docs {
+		result[i] = DocumentTerms{Name: c.names[i], Terms: c.terms(doc, k)}
+	}
+	return result
+}
+
+// terms returns the k words of doc with the highest TF-IDF.
+func (c *Collection) terms(doc *Counter, k int) []Term {
+	n := float64(len(c.docs))
+	terms := make([]Term, 0, len(doc.stats))
+	for w, st := range doc.stats {
+		df := c.df[w]
+		if df == len(c.docs) {
+			continue
+		}
+		tf := float64(st.count) / float64(doc.words)
+		terms = append(terms, Term{Word: w, Count: st.count, TF: tf, DF: df, TFIDF: tf * math.Log(n/float64(df))})
+	}
+	slices.SortFunc(terms, func(a, b Term) int {
+		if c := cmp.Compare(b.TFIDF, a.TFIDF); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Word), string(b.Word))
+	})
+	return terms[:min(max(k, 0), len(terms))]
+}
@@ -0,0 +1,76 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// ExampleCollection
+func ExampleCollection() {
+	c := NewCollection()
+	c.AddString("cats", "the cat sat on the mat, the cat purred")
+	c.AddString("dogs", "the dog sat on the log, the dog barked")
+	c.AddString("birds", "the bird sang")
+	for _, doc := range c.Top(2) {
+		fmt.Println(doc.Name, doc.Terms[0].Word, doc.Terms[1].Word)
+	}
+	// Output:
+	// cats cat mat
+	// dogs dog barked
+	// birds bird sang
+}
+
+func TestCollection_Top(t *testing.T) {
+	c := NewCollection(WithNGrams(1))
+	c.AddString("a", "x x y z")
+	c.AddString("b", "y z")
+	c.AddString("c", "z w")
+	tests := []struct {
+		name     string
+		k        int
+		expected []Term
+	}{
+		{"all", 5, []Term{
+			{Word: "x", Count: 2, TF: 0.5, DF: 1, TFIDF: 0.5 * math.Log(3)},
+			{Word: "y", Count: 1, TF: 0.25, DF: 2, TFIDF: 0.25 * math.Log(1.5)},
+		}},
+		{"one", 1, []Term{{Word: "x", Count: 2, TF: 0.5, DF: 1, TFIDF: 0.5 * math.Log(3)}}},
+		{"none", 0, []Term{}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := c.Top(d.k)
+			if len(got) != 3 || got[0].Name != "a" || got[2].Name != "c" {
+				t.Fatalf("Expected documents a, b and c, got %v", got)
+			}
+			if fmt.Sprint(got[0].Terms) != fmt.Sprint(d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got[0].Terms)
+			}
+		})
+	}
+	if c.Len() != 3 || c.DocumentFrequency("z") != 3 || c.DocumentFrequency("q") != 0 {
+		t.Errorf("Expected 3 documents and z in all of them, got %d and %d", c.Len(), c.DocumentFrequency("z"))
+	}
+}
+
+func TestCollection_Add(t *testing.T) {
+	c := NewCollection()
+	c.AddString("single", "only words")
+	if terms := c.Top(3)[0].Terms; len(terms) != 0 {
+		t.Errorf("Expected no distinctive words of a single document, got %v", terms)
+	}
+	errRead := errors.New("read error")
+	r := io.MultiReader(strings.NewReader("lost words"), iotest.ErrReader(errRead))
+	if err := c.Add("broken", r); !errors.Is(err, errRead) {
+		t.Errorf("Expected %v, got %v", errRead, err)
+	}
+	if c.Len() != 1 || c.DocumentFrequency("lost") != 0 {
+		t.Errorf("Expected the broken document to be left out")
+	}
+}
@@ -0,0 +1,121 @@
+package wordfreq
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits text into words.
+type Tokenizer interface {
+	Tokenize(s string) []Word
+}
+
+// TokenizerFunc is an adapter to use an ordinary function as a Tokenizer.
+type TokenizerFunc func(s string) []Word
+
+// Tokenize calls f(s).
+func (f TokenizerFunc) Tokenize(s string) []Word {
+	return f(s)
+}
+
+// FieldsTokenizer splits text at whitespace like strings.Fields and keeps
+// words as they are, punctuation and case included.
+var FieldsTokenizer Tokenizer = TokenizerFunc(func(s string) []Word {
+	fields := strings.Fields(s)
+	result := make([]Word, len(fields))
+	for i, f := range fields {
+		result[i] = Word(f)
+	}
+	return result
+})
+
+// UnicodeTokenizer is the default Tokenizer. A word is a run of letters,
+// digits and combining marks; apostrophes and hyphens are part of a word
+// when they join two such runs, so "don't" and "well-known" are single words
+// while "-" and "'quoted'" are not. Everything else separates words.
+//
+// Text is normalized to NFC and words are case folded, so "Cat", "cat,"
+// and "CAT." count as the same word "cat". Typographic apostrophes (’, ʼ)
+// become ' and Unicode hyphens (‐, ‑) become -.
+type UnicodeTokenizer struct {
+	// Links keeps URLs, starting with a scheme like "https://" or with "www.",
+	// and email addresses whole as single case folded words, without the punctuation
+	// around them, so that filters like URLFilter can tell them.
+	// Otherwise they are split like other text.
+	Links bool
+}
+
+// Tokenize returns the words of s.
+func (t UnicodeTokenizer) Tokenize(s string) []Word {
+	if !t.Links {
+		return appendWords(nil, []rune(nfc(s)))
+	}
+	var result []Word
+	for field := range strings.FieldsSeq(nfc(s)) {
+		if link := trimLink(field); link != "" {
+			result = append(result, Word(strings.Map(foldRune, link)))
+			continue
+		}
+		result = appendWords(result, []rune(field))
+	}
+	return result
+}
+
+// appendWords appends the words of rs to result like UnicodeTokenizer.
+func appendWords(result []Word, rs []rune) []Word {
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			result = append(result, Word(b.String()))
+			b.Reset()
+		}
+	}
+	for i, r := range rs {
+		switch {
+		case isWordRune(r) || unicode.IsMark(r) && b.Len() > 0:
+			b.WriteRune(foldRune(r))
+		case joiner(r) != 0 && b.Len() > 0 && i+1 < len(rs) && isWordRune(rs[i+1]):
+			b.WriteRune(joiner(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return result
+}
+
+// urlPattern and emailPattern match whole URLs and email addresses.
+var (
+	urlPattern   = regexp.MustCompile(`^(?i:[a-z][a-z0-9+.-]*://|www\.)\S+$`)
+	emailPattern = regexp.MustCompile(`^[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(\.[\p{L}\p{N}-]+)+$`)
+)
+
+// trimLink returns the URL or the email address field consists of without
+// the punctuation around it, an empty string if there is none.
+func trimLink(field string) string {
+	if !strings.Contains(field, "://") && !strings.Contains(field, "@") && !strings.Contains(strings.ToLower(field), "www.") {
+		return ""
+	}
+	link := strings.TrimRight(strings.TrimLeft(field, "\"'([{<«"), "\"'.,;:!?)]}>»")
+	if urlPattern.MatchString(link) || emailPattern.MatchString(link) {
+		return link
+	}
+	return ""
+}
+
+// isWordRune reports whether r is a letter or a digit.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// joiner returns the apostrophe or the hyphen r stands for, 0 for other runes.
+func joiner(r rune) rune {
+	switch r {
+	case '\'', '’', 'ʼ':
+		return '\''
+	case '-', '‐', '‑':
+		return '-'
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package wordfreq
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ExampleUnicodeTokenizer
+func ExampleUnicodeTokenizer() {
+	fmt.Println(UnicodeTokenizer{}.Tokenize("Cat, cat. CAT! Don’t well-known -- 'quoted'"))
+	// Output: [cat cat cat don't well-known quoted]
+}
+
+func TestUnicodeTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    bool
+		input    string
+		expected []Word
+	}{
+		{"punctuation", false, "cat, cat. (cat)", []Word{"cat", "cat", "cat"}},
+		{"case folding", false, "Cat CAT cAt", []Word{"cat", "cat", "cat"}},
+		{"digits", false, "route 66, 2024-01-01", []Word{"route", "66", "2024-01-01"}},
+		{"apostrophes", false, "don't don’t rock'n'roll 'tis dogs'", []Word{"don't", "don't", "rock'n'roll", "tis", "dogs"}},
+		{"hyphens", false, "well-known well‑known -dash- a--b", []Word{"well-known", "well-known", "dash", "a", "b"}},
+		{"cyrillic", false, "Ёлка, ёлка и ЁЛКА.", []Word{"ёлка", "ёлка", "и", "ёлка"}},
+		{"decomposed", false, "caf\u00e9 cafe\u0301 CAFE\u0301", []Word{"caf\u00e9", "caf\u00e9", "caf\u00e9"}},
+		{"final sigma", false, "ΟΔΟΣ οδος οδοσ", []Word{"οδοσ", "οδοσ", "οδοσ"}},
+		{"stray mark", false, "\u0301 a\u0301", []Word{"\u00e1"}},
+		{"urls split", false, "See https://Go.dev/doc", []Word{"see", "https", "go", "dev", "doc"}},
+		{"emails split", false, "Mail <Bob@Example.com>", []Word{"mail", "bob", "example", "com"}},
+		{"urls", true, "See https://Go.dev/doc, (www.example.com). http:// www.", []Word{"see", "https://go.dev/doc", "www.example.com", "http", "www"}},
+		{"emails", true, "Mail <Bob@Example.com>, bob@ or @bob.", []Word{"mail", "bob@example.com", "bob", "or", "bob"}},
+		{"empty", false, " ,.;! ", nil},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if got := (UnicodeTokenizer{Links: d.links}).Tokenize(d.input); !slices.Equal(got, d.expected) {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestFieldsTokenizer(t *testing.T) {
+	got := FieldsTokenizer.Tokenize(" Cat, cat ")
+	if expected := []Word{"Cat,", "cat"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestGetWordsMap_WithTokenizer(t *testing.T) {
+	upper := TokenizerFunc(func(s string) []Word {
+		var result []Word
+		for _, f := range strings.Fields(s) {
+			result = append(result, Word(strings.ToUpper(f)))
+		}
+		return result
+	})
+	tests := []struct {
+		name      string
+		tokenizer Option
+		expected  map[Word]int
+	}{
+		{"default", WithTokenizer(UnicodeTokenizer{}), map[Word]int{"cat": 3}},
+		{"fields", WithTokenizer(FieldsTokenizer), map[Word]int{"Cat,": 1, "cat.": 1, "cat": 1}},
+		{"custom", WithTokenizer(upper), map[Word]int{"CAT,": 1, "CAT.": 1, "CAT": 1}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got := GetWordsMap("Cat, cat. cat", d.tokenizer)
+			if fmt.Sprint(got) != fmt.Sprint(d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidWindow is returned by NewTrends for windows which cannot be used.
+var ErrInvalidWindow = errors.New("invalid window")
+
+// ErrNoTimestamp is returned by ParseTimestamped for lines without a timestamp.
+var ErrNoTimestamp = errors.New("no timestamp")
+
+// TrendWindow is the length of windows and the distance between their starts.
+// With Step equal to Size the windows are tumbling: every line falls into one window.
+// With a shorter Step they are sliding and overlap.
+type TrendWindow struct {
+	Size time.Duration
+	Step time.Duration // Size if zero
+}
+
+// Window is a window of time with the words trending in it: those relatively
+// more frequent than in the previous window of the same size, which ends where
+// the window starts. Trending words are described by their keyness, Target
+// being the count in the window and Reference the count in the previous one.
+type Window struct {
+	Start, End time.Time
+	Words      int // number of words in the window
+	Trending   []Keyness
+}
+
+// trendBucket holds the counts of words of a step of time.
+type trendBucket struct {
+	start  time.Time
+	counts map[Word]int
+	words  int
+}
+
+// Trends counts the words of timestamped lines in windows of time and finds
+// the words trending in every window. Lines must come in time order,
+// a line older than the ones before counts as if it came at the same time as them.
+// The zero value is not usable, create it with NewTrends.
+type Trends struct {
+	opts    options
+	size    time.Duration
+	step    time.Duration
+	k       int
+	by      Statistic
+	th      Thresholds
+	buckets []trendBucket // the last steps of the window and the previous one, oldest first
+}
+
+// NewTrends returns Trends reporting at most k trending words of every window,
+// the most trending first by the statistic by, which pass the thresholds.
+// Windows start at multiples of the step since the zero time, e.g. at full hours
+// for an hour. Size must be a positive multiple of the step.
+func NewTrends(w TrendWindow, k int, by Statistic, th Thresholds, opts ...Option) (*Trends, error) {
+	if w.Step == 0 {
+		w.Step = w.Size
+	}
+	if w.Size <= 0 || w.Step <= 0 || w.Size%w.Step != 0 {
+		return nil, fmt.Errorf("%w: size %v, step %v, the size must be a positive multiple of the step", ErrInvalidWindow, w.Size, w.Step)
+	}
+	return &Trends{opts: newOptions(opts), size: w.Size, step: w.Step, k: k, by: by, th: th}, nil
+}
+
+// Add counts the words of a line of text written at the given time and returns
+// the windows which ended before it with words in them, the earliest first.
+func (t *Trends) Add(at time.Time, text string) []Window {
+	start := at.Truncate(t.step)
+	var result []Window
+	if len(t.buckets) == 0 {
+		t.buckets = append(t.buckets, trendBucket{start: start, counts: map[Word]int{}})
+	}
+	for last := t.buckets[len(t.buckets)-1]; last.start.Before(start); last = t.buckets[len(t.buckets)-1] {
+		end := last.start.Add(t.step)
+		if w, ok := t.window(end); ok {
+			result = append(result, w)
+		}
+		if t.empty() {
+			// nothing to report until the next words, skip the silence at once
+			end = start
+			t.buckets = t.buckets[:0]
+		}
+		t.buckets = append(t.buckets, trendBucket{start: end, counts: map[Word]int{}})
+		if n := int(2 * t.size / t.step); len(t.buckets) > n {
+			t.buckets = t.buckets[len(t.buckets)-n:]
+		}
+	}
+	words, _ := t.opts.tokenizeForms(text)
+	words, _ = (&ngramStream{n: t.opts.ngramWords()}).next(words, nil)
+	b := &t.buckets[len(t.buckets)-1]
+	for _, w := range words {
+		b.counts[w]++
+	}
+	b.words += len(words)
+	return result
+}
+
+// Flush returns the window ending with the step of the last line, if it has words,
+// and forgets all words counted.
+func (t *Trends) Flush() []Window {
+	if len(t.buckets) == 0 {
+		return nil
+	}
+	var result []Window
+	if w, ok := t.window(t.buckets[len(t.buckets)-1].start.Add(t.step)); ok {
+		result = append(result, w)
+	}
+	t.buckets = nil
+	return result
+}
+
+// empty reports whether no words are kept.
+func (t *Trends) empty() bool {
+	for _, b := range t.buckets {
+		if b.words > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// window returns the window ending at end with its trending words,
+// false if there are no words in it.
+func (t *Trends) window(end time.Time) (Window, bool) {
+	start := end.Add(-t.size)
+	current, previous := map[Word]int{}, map[Word]int{}
+	words, previousWords := 0, 0
+	for _, b := range t.buckets {
+		switch {
+		case !b.start.Before(start) && b.start.Before(end):
+			words += b.words
+			for w, n := range b.counts {
+				current[w] += n
+			}
+		case !b.start.Before(start.Add(-t.size)) && b.start.Before(start):
+			previousWords += b.words
+			for w, n := range b.counts {
+				previous[w] += n
+			}
+		}
+	}
+	if words == 0 {
+		return Window{}, false
+	}
+	trending := make([]Keyness, 0, t.k)
+	for _, k := range compare(current, previous, words, previousWords, t.by, t.th) {
+		if len(trending) == t.k {
+			break
+		}
+		// the relative frequency must rise, not only the smoothed one of LogRatio
+		if k.Target*previousWords > k.Reference*words {
+			trending = append(trending, k)
+		}
+	}
+	return Window{Start: start, End: end, Words: words, Trending: trending}, true
+}
+
+// timestampLayouts - layouts of timestamps accepted by ParseTimestamped,
+// the number of fields separated by spaces they take
+var timestampLayouts = []struct {
+	layout string
+	fields int
+}{
+	{time.RFC3339Nano, 1},
+	{"2006-01-02T15:04:05.999999999", 1},
+	{"2006-01-02 15:04:05.999999999Z07:00", 2},
+	{"2006-01-02 15:04:05.999999999", 2},
+}
+
+// ParseTimestamped splits a line into the timestamp at its beginning and the text
+// following it. The timestamp may be in RFC 3339 format, "2006-01-02 15:04:05"
+// with optional fractional seconds and zone, or a number of seconds since
+// the Unix epoch, and may be enclosed in square brackets. Outside brackets the
+// number of seconds must have at least 9 digits before the point, dates since 1973,
+// so that a line like "2024 was great" is not taken for a timestamp.
+// Timestamps without a zone are in UTC.
+func ParseTimestamped(line string) (time.Time, string, error) {
+	rest := strings.TrimSpace(line)
+	if strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return time.Time{}, "", fmt.Errorf("%w: %q", ErrNoTimestamp, line)
+		}
+		at, err := parseTimestamp(strings.TrimSpace(rest[1:end]))
+		return at, strings.TrimSpace(rest[end+1:]), err
+	}
+	fields := strings.SplitN(rest, " ", 3)
+	for _, l := range timestampLayouts {
+		if len(fields) < l.fields {
+			continue
+		}
+		if at, err := time.Parse(l.layout, strings.Join(fields[:l.fields], " ")); err == nil {
+			return at, strings.Join(fields[l.fields:], " "), nil
+		}
+	}
+	at, err := parseUnix(fields[0])
+	if whole, _, _ := strings.Cut(fields[0], "."); err != nil || len(whole) < minUnixDigits {
+		return time.Time{}, "", fmt.Errorf("%w: %q", ErrNoTimestamp, line)
+	}
+	return at, strings.Join(fields[1:], " "), nil
+}
+
+// parseTimestamp parses a whole string as a timestamp of ParseTimestamped.
+func parseTimestamp(s string) (time.Time, error) {
+	for _, l := range timestampLayouts {
+		if at, err := time.Parse(l.layout, s); err == nil {
+			return at, nil
+		}
+	}
+	if at, err := parseUnix(s); err == nil {
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrNoTimestamp, s)
+}
+
+// minUnixDigits is the least number of digits of seconds since the Unix epoch
+// before the point in a timestamp outside brackets.
+const minUnixDigits = 9
+
+// parseUnix parses a number of seconds since the Unix epoch, possibly fractional.
+func parseUnix(s string) (time.Time, error) {
+	if s == "" || strings.Trim(s, "0123456789.") != "" {
+		return time.Time{}, ErrNoTimestamp
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, ErrNoTimestamp
+	}
+	whole := int64(secs)
+	return time.Unix(whole, int64((secs-float64(whole))*1e9)).UTC(), nil
+}
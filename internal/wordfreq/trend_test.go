@@ -0,0 +1,156 @@
+package wordfreq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// ExampleTrends
+func ExampleTrends() {
+	t, _ := NewTrends(TrendWindow{Size: time.Hour}, 2, LogRatio, Thresholds{MinCount: 2})
+	var windows []Window
+	for _, line := range []string{
+		"2024-05-01T10:05:00Z deploy went fine",
+		"2024-05-01T10:40:00Z lunch anyone",
+		"2024-05-01T11:10:00Z the build is red",
+		"2024-05-01T11:15:00Z red build again, build is red",
+		"2024-05-01T12:01:00Z fixed",
+	} {
+		at, text, _ := ParseTimestamped(line)
+		windows = append(windows, t.Add(at, text)...)
+	}
+	windows = append(windows, t.Flush()...)
+	for _, w := range windows {
+		fmt.Println(w.Start.Format("15:04"), w.End.Format("15:04"), w.Words)
+		for _, k := range w.Trending {
+			fmt.Printf("  %s %d %d\n", k.Word, k.Target, k.Reference)
+		}
+	}
+	// Output:
+	// 10:00 11:00 5
+	// 11:00 12:00 10
+	//   build 3 0
+	//   red 3 0
+	// 12:00 13:00 1
+}
+
+// at returns the time of minutes past midnight of a day.
+func at(minutes int) time.Time {
+	return time.Date(2024, 5, 1, 0, minutes, 0, 0, time.UTC)
+}
+
+func TestTrends(t *testing.T) {
+	type line struct {
+		minute int
+		text   string
+	}
+	tests := []struct {
+		name     string
+		window   TrendWindow
+		lines    []line
+		expected []string // "start-end words: trending words"
+	}{
+		{"tumbling", TrendWindow{Size: 10 * time.Minute},
+			[]line{{0, "a b"}, {5, "a"}, {12, "b b c"}, {25, "c c a"}},
+			[]string{"0-10 3: []", "10-20 3: [c b]", "20-30 3: [a c]"}},
+		{"sliding", TrendWindow{Size: 10 * time.Minute, Step: 5 * time.Minute},
+			[]line{{0, "a"}, {6, "b"}, {11, "b"}, {16, "c"}},
+			[]string{"-5-5 1: []", "0-10 2: []", "5-15 2: [b]", "10-20 2: [c]"}},
+		{"silence skipped", TrendWindow{Size: time.Minute},
+			[]line{{0, "a"}, {1000, "b"}},
+			[]string{"0-1 1: []", "1000-1001 1: []"}},
+		{"silence in between", TrendWindow{Size: time.Minute},
+			[]line{{0, "a"}, {2, "b"}},
+			[]string{"0-1 1: []", "2-3 1: []"}},
+		{"out of order", TrendWindow{Size: 10 * time.Minute},
+			[]line{{12, "a"}, {3, "b"}, {25, "b b"}},
+			[]string{"10-20 2: []", "20-30 2: [b]"}},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			trends, err := NewTrends(d.window, 2, LogLikelihood, Thresholds{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var windows []Window
+			for _, l := range d.lines {
+				windows = append(windows, trends.Add(at(l.minute), l.text)...)
+			}
+			windows = append(windows, trends.Flush()...)
+			got := make([]string, len(windows))
+			for i, w := range windows {
+				words := make([]Word, len(w.Trending))
+				for j, k := range w.Trending {
+					words[j] = k.Word
+				}
+				minutes := func(t time.Time) int { return int(t.Sub(at(0)) / time.Minute) }
+				got[i] = fmt.Sprintf("%d-%d %d: %v", minutes(w.Start), minutes(w.End), w.Words, words)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(d.expected) {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewTrends(t *testing.T) {
+	tests := []struct {
+		name   string
+		window TrendWindow
+		err    error
+	}{
+		{"tumbling", TrendWindow{Size: time.Hour}, nil},
+		{"sliding", TrendWindow{Size: time.Hour, Step: 15 * time.Minute}, nil},
+		{"no size", TrendWindow{}, ErrInvalidWindow},
+		{"step not dividing size", TrendWindow{Size: time.Hour, Step: 7 * time.Minute}, ErrInvalidWindow},
+		{"negative step", TrendWindow{Size: time.Hour, Step: -time.Minute}, ErrInvalidWindow},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			if _, err := NewTrends(d.window, 1, LogLikelihood, Thresholds{}); !errors.Is(err, d.err) {
+				t.Errorf("Expected %v, got %v", d.err, err)
+			}
+		})
+	}
+}
+
+func TestParseTimestamped(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected time.Time
+		text     string
+		err      error
+	}{
+		{"rfc 3339", "2024-05-01T10:05:00Z hello there", time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC), "hello there", nil},
+		{"zone", "2024-05-01T10:05:00+02:00 hi", time.Date(2024, 5, 1, 8, 5, 0, 0, time.UTC), "hi", nil},
+		{"date and time", "2024-05-01 10:05:00.5 hi all", time.Date(2024, 5, 1, 10, 5, 0, 5e8, time.UTC), "hi all", nil},
+		{"brackets", "[2024-05-01 10:05:00] <bob> hi", time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC), "<bob> hi", nil},
+		{"unix", "1714557900 hi", time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC), "hi", nil},
+		{"unix in brackets", "[1714557900.25]hi", time.Date(2024, 5, 1, 10, 5, 0, 25e7, time.UTC), "hi", nil},
+		{"timestamp only", "2024-05-01T10:05:00Z", time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC), "", nil},
+		{"year", "2024 was great", time.Time{}, "", ErrNoTimestamp},
+		{"short number", "99999999.5 hi", time.Time{}, "", ErrNoTimestamp},
+		{"short number in brackets", "[60] hi", time.Unix(60, 0).UTC(), "hi", nil},
+		{"no timestamp", "hello there", time.Time{}, "", ErrNoTimestamp},
+		{"not a number", "inf hi", time.Time{}, "", ErrNoTimestamp},
+		{"unclosed bracket", "[2024-05-01 hi", time.Time{}, "", ErrNoTimestamp},
+		{"empty", "", time.Time{}, "", ErrNoTimestamp},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			got, text, err := ParseTimestamped(d.line)
+			if !errors.Is(err, d.err) {
+				t.Fatalf("Expected %v, got %v", d.err, err)
+			}
+			if !got.Equal(d.expected) || text != d.text {
+				t.Errorf("Expected %v %q, got %v %q", d.expected, d.text, got, text)
+			}
+		})
+	}
+}
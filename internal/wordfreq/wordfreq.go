@@ -2,9 +2,21 @@
 //
 // It includes functions to:
 //   - Parse user input for a desired number of top words (ParseK),
-//   - Count word frequencies in a given string (GetWordsMap),
-//   - Sort words by frequency (getWordsSlice),
-//   - Return the top-k most frequent words (GetResultWordsSlice).
+//   - Split text into words (Tokenizer, UnicodeTokenizer by default),
+//   - Choose the words to count by patterns, length and kind (WithFilters),
+//   - Count word frequencies in a given string (GetWordsMap) or in text of any size (Counter),
+//   - Count word stems (WithNormalizer) or word and character n-grams (WithNGrams, WithCharNGrams),
+//   - Sort words by frequency (getSortedWords),
+//   - Return the top-k most frequent words (GetResultWordsSlice) with
+//     a configurable order of words with equal frequency (WithTieBreak),
+//     or with their counts, frequencies and ranks (GetResultEntries),
+//   - Draw the entries as a bar chart (WriteBars) or an SVG word cloud (WriteSVG),
+//   - Find words over- and underused in one text compared with another (Compare),
+//   - Find the words distinguishing documents of a collection by TF-IDF (Collection),
+//   - Keep counts of a growing corpus in an append-only file (Index, AppendIndex),
+//   - Find words trending in windows of time of timestamped lines (Trends),
+//   - Find the words occurring near a word and score their association (Cooccurrences),
+//   - Measure the vocabulary, sentences and readability of a text (GetTextStats, StatsCounter).
 //
 // The Word type is a custom string type used as a map key for clarity and consistency.
 package wordfreq
@@ -14,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -54,40 +65,31 @@ func ParseK(s string, reader *bufio.Reader, writer io.Writer) (int, error) {
 
 // GetWordsMap takes a string, splits it into words, and returns a map
 // where each key is a Word and each value is the number of occurrences of that word.
-func GetWordsMap(s string) map[Word]int {
-	result := map[Word]int{}
-	words := strings.Fields(s)
-	for _, v := range words {
-		result[Word(v)]++
-	}
-	return result
+// Words are found by UnicodeTokenizer unless WithTokenizer says otherwise.
+func GetWordsMap(s string, opts ...Option) map[Word]int {
+	c := NewCounter(opts...)
+	c.Add(s)
+	return c.Counts()
 }
 
-// getWordsSlice takes a map of Words to their frequencies and returns a slice
-// of Words sorted in descending order of frequency.
+// getSortedWords takes a map of Words to their frequencies and returns a slice
+// of Words sorted in descending order of frequency, then lexicographically.
 func getSortedWords(m map[Word]int) WordSlice {
-	result := make([]Word, 0, len(m))
-	for k := range m {
-		result = append(result, k)
+	stats := make(map[Word]wordStat, len(m))
+	for k, v := range m {
+		stats[k] = wordStat{count: v}
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return m[result[i]] > m[result[j]]
-	})
-	return result
+	return rankWords(stats, Lexicographic)
 }
 
-// GetResultWordsSlice returns the first k words from the slice w.
-// If k is greater than the length of w, it returns all words in w.
-func GetResultWordsSlice(s string, k int) WordSlice {
-	m := GetWordsMap(s)
-	sorted := getSortedWords(m)
-	result := WordSlice{}
-	if k >= len(sorted) {
-		result = sorted[:]
-	} else if k < len(sorted) && len(sorted) > 0 {
-		result = sorted[:k]
-	}
-	return result
+// GetResultWordsSlice returns the k most frequent words of s.
+// If k is greater than the number of distinct words, it returns all of them.
+// Words with equal frequency are ordered lexicographically unless
+// WithTieBreak says otherwise.
+func GetResultWordsSlice(s string, k int, opts ...Option) WordSlice {
+	c := NewCounter(opts...)
+	c.Add(s)
+	return c.Top(k)
 }
 
 // PrintWords method for WordSlice to print all words in the slice
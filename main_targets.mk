@@ -13,6 +13,7 @@ build:
 	@echo "make slicecross - run Find common values in two crossing slices"
 	@echo "make visitlog - run imitation of Visit log\n"
 	@echo "tests and coverage - make test, make coverage"
+	@echo "fuzzing - make fuzz FUZZ_TIME=1m"
 	@echo "pkgsite documentation - make dvi"
 	@echo "godoc dvi - make dvi_<exe_name>"
 .PHONY: build